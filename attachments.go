@@ -0,0 +1,34 @@
+package main
+
+import (
+	"path/filepath"
+
+	"micv/client"
+)
+
+// AttachmentConfig overrides the payload field and size limits of multipart
+// submissions with attachments
+type AttachmentConfig struct {
+	PayloadField   string `json:"payload_field,omitempty"`
+	MaxFileSizeKB  int    `json:"max_file_size_kb,omitempty"`
+	MaxTotalSizeKB int    `json:"max_total_size_kb,omitempty"`
+}
+
+// options converts the settings to client options; unset values use the client defaults
+func (a AttachmentConfig) options() client.AttachmentOptions {
+	return client.AttachmentOptions{
+		PayloadField: a.PayloadField,
+		MaxFileSize:  int64(a.MaxFileSizeKB) * 1024,
+		MaxTotalSize: int64(a.MaxTotalSizeKB) * 1024,
+	}
+}
+
+// resolveAttachmentPaths makes relative attachment paths relative to dir,
+// the directory of the data file that references them
+func resolveAttachmentPaths(appData *ApplicationData, dir string) {
+	for i, attachment := range appData.Attachments {
+		if attachment.Path != "" && !filepath.IsAbs(attachment.Path) {
+			appData.Attachments[i].Path = filepath.Join(dir, attachment.Path)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeAttachmentData writes a data file in dir that attaches resume.pdf by relative path
+func writeAttachmentData(t *testing.T, dir string) string {
+	t.Helper()
+	data := `{
+  "name": "John Doe",
+  "email": "john@example.com",
+  "job_title": "Software Engineer",
+  "attachments": [{"field": "resume", "path": "docs/resume.pdf"}]
+}`
+	filename := filepath.Join(dir, "applicant.json")
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	return filename
+}
+
+func TestLoadApplicationDataResolvesAttachmentPaths(t *testing.T) {
+	dir := t.TempDir()
+	appData, err := LoadApplicationData(writeAttachmentData(t, dir))
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expected := filepath.Join(dir, "docs", "resume.pdf")
+	if len(appData.Attachments) != 1 || appData.Attachments[0].Path != expected {
+		t.Errorf("Expected attachment path %s, got %+v", expected, appData.Attachments)
+	}
+}
+
+func TestValidateFilesChecksAttachments(t *testing.T) {
+	dir := t.TempDir()
+	filename := writeAttachmentData(t, dir)
+
+	results := ValidateFiles([]string{filename}, nil, DataOptions{})
+	if len(results) != 1 || results[0].Passed() || !strings.Contains(results[0].Err.Error(), `attachment "resume"`) {
+		t.Fatalf("Expected the missing attachment to fail validation, got %+v", results)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docs", "resume.pdf"), []byte("%PDF-1.7\n"+strings.Repeat("x", 2048)), 0644); err != nil {
+		t.Fatalf("Failed to write attachment: %v", err)
+	}
+	if results := ValidateFiles([]string{filename}, nil, DataOptions{}); !results[0].Passed() {
+		t.Errorf("Expected the data file to pass, got %v", results[0].Err)
+	}
+	if results := ValidateFiles([]string{filename}, nil, DataOptions{Attachments: AttachmentConfig{MaxFileSizeKB: 1}}); results[0].Passed() {
+		t.Error("Expected the configured size limit to apply")
+	}
+}
+
+func TestApplicationServiceSubmitsAttachments(t *testing.T) {
+	dir := t.TempDir()
+	resume := filepath.Join(dir, "resume.pdf")
+	if err := os.WriteFile(resume, []byte("%PDF-1.7\n"), 0644); err != nil {
+		t.Fatalf("Failed to write attachment: %v", err)
+	}
+
+	deps := NewMockDependencies()
+	deps.config.Attachments.PayloadField = "data"
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	var contentType, field string
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		contentType = req.Header.Get("Content-Type")
+		if err := req.ParseMultipartForm(1 << 20); err == nil {
+			field = req.FormValue("data")
+		}
+		return createResponse(201, `{"status":"success"}`), nil
+	}
+
+	appData := ApplicationData{
+		Name:        "John Doe",
+		Email:       "john@example.com",
+		JobTitle:    "Software Engineer",
+		Attachments: []Attachment{{Field: "resume", Path: resume}},
+	}
+	if err := NewApplicationService(deps).SubmitApplication(context.Background(), appData); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !strings.HasPrefix(contentType, "multipart/form-data; boundary=") {
+		t.Errorf("Expected a multipart request, got %q", contentType)
+	}
+	if !strings.Contains(field, `"name": "John Doe"`) {
+		t.Errorf("Expected the JSON payload in the configured field, got %q", field)
+	}
+}
+
+func TestValidateConfigAttachmentLimits(t *testing.T) {
+	config := DefaultConfig()
+	config.Attachments.MaxTotalSizeKB = -1
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected an error for a negative attachment limit")
+	}
+}
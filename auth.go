@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"micv/client"
+)
+
+// Authorization schemes supported by the application request
+const (
+	AuthSchemeRaw    = "raw"
+	AuthSchemeBearer = "bearer"
+	AuthSchemeStatic = "static"
+	AuthSchemeHMAC   = "hmac"
+)
+
+// AuthConfig selects and configures the authorization scheme
+type AuthConfig struct {
+	Scheme          string `json:"scheme,omitempty"`
+	TokenEnv        string `json:"token_env,omitempty"`
+	TokenFile       string `json:"token_file,omitempty"`
+	HMACKeyEnv      string `json:"hmac_key_env,omitempty"`
+	HMACKeyFile     string `json:"hmac_key_file,omitempty"`
+	SignatureHeader string `json:"signature_header,omitempty"`
+}
+
+// Authenticators from the client package
+type (
+	Authenticator            = client.Authenticator
+	RawTokenAuthenticator    = client.RawTokenAuthenticator
+	BearerAuthenticator      = client.BearerAuthenticator
+	StaticTokenAuthenticator = client.StaticTokenAuthenticator
+	HMACAuthenticator        = client.HMACAuthenticator
+)
+
+// NewAuthenticator creates the authenticator selected by the auth configuration
+func NewAuthenticator(config AuthConfig) (Authenticator, error) {
+	switch strings.ToLower(config.Scheme) {
+	case "", AuthSchemeRaw:
+		return RawTokenAuthenticator{}, nil
+	case AuthSchemeBearer:
+		return BearerAuthenticator{}, nil
+	case AuthSchemeStatic:
+		token, err := readSecretSource(config.TokenEnv, config.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("static token: %w", err)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("static token: token_env or token_file is required")
+		}
+		return StaticTokenAuthenticator{Token: token}, nil
+	case AuthSchemeHMAC:
+		key, err := readSecretSource(config.HMACKeyEnv, config.HMACKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("hmac key: %w", err)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("hmac key: hmac_key_env or hmac_key_file is required")
+		}
+		return HMACAuthenticator{Key: []byte(key), Header: config.SignatureHeader}, nil
+	default:
+		return nil, fmt.Errorf("unsupported auth scheme: %s", config.Scheme)
+	}
+}
+
+// readSecretSource reads a secret from an environment variable or a file, in that order
+func readSecretSource(envName, filename string) (string, error) {
+	if envName != "" {
+		if value := os.Getenv(envName); value != "" {
+			return value, nil
+		}
+	}
+
+	if filename != "" {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	return "", nil
+}
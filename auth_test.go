@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"micv/client"
+)
+
+func TestAuthenticators(t *testing.T) {
+	body := []byte(`{"name":"John Doe"}`)
+
+	tests := []struct {
+		name              string
+		auth              Authenticator
+		token             string
+		expectedAuth      string
+		expectedSignature string
+	}{
+		{
+			name:         "raw token",
+			auth:         RawTokenAuthenticator{},
+			token:        "token123",
+			expectedAuth: "token123",
+		},
+		{
+			name:         "bearer prefix",
+			auth:         BearerAuthenticator{},
+			token:        "token123",
+			expectedAuth: "Bearer token123",
+		},
+		{
+			name:         "bearer prefix is not duplicated",
+			auth:         BearerAuthenticator{},
+			token:        "Bearer token123",
+			expectedAuth: "Bearer token123",
+		},
+		{
+			name:              "hmac with configured key",
+			auth:              HMACAuthenticator{Key: []byte("key")},
+			token:             "token123",
+			expectedAuth:      "token123",
+			expectedSignature: "sha256=" + client.SignBody([]byte("key"), body),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "https://example.com/apply", nil)
+			if err := tt.auth.Apply(req, tt.token, body); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			if got := req.Header.Get("Authorization"); got != tt.expectedAuth {
+				t.Errorf("Expected Authorization %q, got %q", tt.expectedAuth, got)
+			}
+			if got := req.Header.Get(client.DefaultSignatureHeader); got != tt.expectedSignature {
+				t.Errorf("Expected signature %q, got %q", tt.expectedSignature, got)
+			}
+		})
+	}
+}
+
+func TestHMACAuthenticatorRequiresKey(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://example.com/apply", nil)
+	if err := (HMACAuthenticator{}).Apply(req, "token123", []byte(`{}`)); !errors.Is(err, client.ErrMissingHMACKey) {
+		t.Errorf("Expected ErrMissingHMACKey, got %v", err)
+	}
+	if req.Header.Get(client.DefaultSignatureHeader) != "" {
+		t.Error("Expected no signature without a key")
+	}
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	t.Setenv("MICV_TEST_TOKEN", "env-token")
+
+	tests := []struct {
+		name          string
+		config        AuthConfig
+		expectedToken string
+		expectStatic  bool
+		errorContains string
+	}{
+		{name: "default is raw", config: AuthConfig{}},
+		{name: "bearer", config: AuthConfig{Scheme: "Bearer"}},
+		{name: "hmac", config: AuthConfig{Scheme: "hmac", HMACKeyEnv: "MICV_TEST_TOKEN"}},
+		{
+			name:          "static from env wins over file",
+			config:        AuthConfig{Scheme: "static", TokenEnv: "MICV_TEST_TOKEN", TokenFile: tokenFile},
+			expectedToken: "env-token",
+			expectStatic:  true,
+		},
+		{
+			name:          "static from file",
+			config:        AuthConfig{Scheme: "static", TokenFile: tokenFile},
+			expectedToken: "file-token",
+			expectStatic:  true,
+		},
+		{
+			name:          "static without source",
+			config:        AuthConfig{Scheme: "static"},
+			errorContains: "token_env or token_file is required",
+		},
+		{
+			name:          "hmac without key",
+			config:        AuthConfig{Scheme: "hmac"},
+			errorContains: "hmac_key_env or hmac_key_file is required",
+		},
+		{
+			name:          "hmac with unset key variable",
+			config:        AuthConfig{Scheme: "hmac", HMACKeyEnv: "MICV_TEST_UNSET_KEY"},
+			errorContains: "hmac_key_env or hmac_key_file is required",
+		},
+		{
+			name:          "unknown scheme",
+			config:        AuthConfig{Scheme: "digest"},
+			errorContains: "unsupported auth scheme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := NewAuthenticator(tt.config)
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error containing %q, got: %v", tt.errorContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			token, static := auth.StaticToken()
+			if static != tt.expectStatic || token != tt.expectedToken {
+				t.Errorf("Expected static token %q (%v), got %q (%v)", tt.expectedToken, tt.expectStatic, token, static)
+			}
+		})
+	}
+}
+
+func TestApplicationServiceStaticToken(t *testing.T) {
+	deps := NewMockDependencies()
+	deps.authenticator = StaticTokenAuthenticator{Token: "static-token"}
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		t.Error("Secret endpoint must not be called with a static token")
+		return createResponse(500, ""), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if got := req.Header.Get("Authorization"); got != "static-token" {
+			t.Errorf("Expected static token in Authorization header, got %q", got)
+		}
+		return createResponse(200, `{"status":"success"}`), nil
+	}
+
+	err := NewApplicationService(deps).SubmitApplication(context.Background(), ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	})
+	if err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+}
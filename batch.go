@@ -0,0 +1,506 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"micv/client"
+)
+
+// Batch entry statuses
+const (
+	BatchStatusSubmitted = "submitted"
+	BatchStatusFailed    = "failed"
+	BatchStatusSkipped   = "skipped"
+	BatchStatusInvalid   = "invalid"
+)
+
+// BatchEntry is a single applicant taken from a batch input
+type BatchEntry struct {
+	ID   string
+	Data ApplicationData
+}
+
+// BatchResult records the outcome of a single batch entry. Key is the
+// idempotency key of the entry's payload, so resume state follows the
+// applicant rather than its position in the input.
+type BatchResult struct {
+	ID          string    `json:"id"`
+	Key         string    `json:"key"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// BatchOptions configures a batch run
+type BatchOptions struct {
+	Input       string
+	Concurrency int
+	StateFile   string
+	ReportFile  string
+}
+
+// csvCoreColumns are mapped to top-level ApplicationData fields; any other
+// column becomes an extra_information field, with dots creating nested objects
+var csvCoreColumns = map[string]bool{
+	"name":          true,
+	"email":         true,
+	"job_title":     true,
+	"final_attempt": true,
+}
+
+// LoadBatchEntries loads applicants from a CSV file or a directory of JSON data files
+func LoadBatchEntries(input string) ([]BatchEntry, error) {
+	info, err := os.Stat(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open batch input: %w", err)
+	}
+
+	if info.IsDir() {
+		return loadBatchDirectory(input)
+	}
+	return loadBatchCSV(input)
+}
+
+// loadBatchDirectory loads every *.json file in dir, in name order
+func loadBatchDirectory(dir string) ([]BatchEntry, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list batch directory: %w", err)
+	}
+	sort.Strings(files)
+
+	entries := make([]BatchEntry, 0, len(files))
+	for _, file := range files {
+		data, err := readApplicationDataFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		entries = append(entries, BatchEntry{ID: filepath.Base(file), Data: *data})
+	}
+
+	return entries, nil
+}
+
+// readApplicationDataFile decodes a data file, expanding variables and file
+// references, without validating it
+func readApplicationDataFile(filename string) (*ApplicationData, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data file: %w", err)
+	}
+
+	if data, err = expandReferences(data, filepath.Dir(filename)); err != nil {
+		return nil, fmt.Errorf("failed to decode data file: %w", err)
+	}
+
+	var appData ApplicationData
+	if err := json.Unmarshal(data, &appData); err != nil {
+		return nil, fmt.Errorf("failed to decode data file: %w", err)
+	}
+	resolveAttachmentPaths(&appData, filepath.Dir(filename))
+
+	return &appData, nil
+}
+
+// loadBatchCSV loads applicants from a CSV file with a header row
+func loadBatchCSV(filename string) ([]BatchEntry, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open batch CSV: %w", err)
+	}
+	defer file.Close()
+
+	return parseBatchCSV(file)
+}
+
+// parseBatchCSV parses applicants from CSV content with a header row
+func parseBatchCSV(r io.Reader) ([]BatchEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+
+	var entries []BatchEntry
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+
+		data, err := csvRecordToApplicationData(header, record)
+		if err != nil {
+			return nil, fmt.Errorf("CSV row %d: %w", row, err)
+		}
+		entries = append(entries, BatchEntry{ID: fmt.Sprintf("row-%d", row), Data: data})
+	}
+
+	return entries, nil
+}
+
+// csvRecordToApplicationData maps a CSV record to application data
+func csvRecordToApplicationData(header, record []string) (ApplicationData, error) {
+	var appData ApplicationData
+	extra := make(map[string]interface{})
+
+	for i, column := range header {
+		if i >= len(record) {
+			break
+		}
+		value := strings.TrimSpace(record[i])
+
+		switch column {
+		case "name":
+			appData.Name = value
+		case "email":
+			appData.Email = value
+		case "job_title":
+			appData.JobTitle = value
+		case "final_attempt":
+			if value == "" {
+				continue
+			}
+			finalAttempt, err := strconv.ParseBool(value)
+			if err != nil {
+				return appData, fmt.Errorf("invalid final_attempt %q (expected true or false)", value)
+			}
+			if finalAttempt {
+				appData.FinalAttempt = &finalAttempt
+			}
+		default:
+			if value != "" {
+				setNestedValue(extra, strings.Split(column, "."), value)
+			}
+		}
+	}
+
+	if len(extra) > 0 {
+		appData.ExtraInformation = extra
+	}
+	return appData, nil
+}
+
+// setNestedValue stores value in target, creating nested maps along path
+func setNestedValue(target map[string]interface{}, path []string, value string) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := target[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			target[key] = child
+		}
+		target = child
+	}
+	target[path[len(path)-1]] = value
+}
+
+// BatchState tracks completed entries so an interrupted batch can resume
+type BatchState struct {
+	path    string
+	mu      sync.Mutex
+	Results map[string]BatchResult `json:"results"`
+}
+
+// LoadBatchState loads the batch state file, treating a missing file as empty
+func LoadBatchState(path string) (*BatchState, error) {
+	state := &BatchState{path: path, Results: make(map[string]BatchResult)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to decode batch state: %w", err)
+	}
+	if state.Results == nil {
+		state.Results = make(map[string]BatchResult)
+	}
+	return state, nil
+}
+
+// Submitted returns the recorded result when the payload with key was already
+// submitted successfully
+func (s *BatchState) Submitted(key string) (BatchResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, ok := s.Results[key]
+	return result, ok && result.Status == BatchStatusSubmitted
+}
+
+// Record stores a result and persists the state file
+func (s *BatchState) Record(result BatchResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Results[result.Key] = result
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode batch state: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write batch state: %w", err)
+	}
+	return nil
+}
+
+// validateBatchEntries validates every entry and its attachments up front,
+// returning results for invalid ones
+func validateBatchEntries(entries []BatchEntry, attachments client.AttachmentOptions) []BatchResult {
+	var invalid []BatchResult
+	for _, entry := range entries {
+		err := validateApplicationDataFunctional(entry.Data).Error
+		if err == nil {
+			err = client.CheckAttachments(entry.Data.Attachments, attachments)
+		}
+		if err != nil {
+			invalid = append(invalid, BatchResult{
+				ID:     entry.ID,
+				Name:   entry.Data.Name,
+				Email:  entry.Data.Email,
+				Status: BatchStatusInvalid,
+				Error:  err.Error(),
+			})
+		}
+	}
+	return invalid
+}
+
+// RunBatch submits entries with bounded concurrency, skipping those already submitted
+func RunBatch(ctx context.Context, deps Dependencies, entries []BatchEntry, state *BatchState, concurrency int) []BatchResult {
+	logger := deps.Logger().With("operation", "batch")
+	service := NewApplicationService(deps)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(entries))
+	keys := make([]string, len(entries))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = submitBatchEntry(ctx, deps, service, entries[i], keys[i], state, logger)
+			}
+		}()
+	}
+
+	for i, entry := range entries {
+		key, err := IdempotencyKey(deps.Config().ApplicationURL, entry.Data)
+		if err != nil {
+			results[i] = BatchResult{ID: entry.ID, Name: entry.Data.Name, Email: entry.Data.Email, Status: BatchStatusFailed, Error: err.Error()}
+			continue
+		}
+		keys[i] = key
+
+		if previous, ok := state.Submitted(key); ok {
+			previous.ID = entry.ID
+			previous.Status = BatchStatusSkipped
+			results[i] = previous
+			continue
+		}
+		if ctx.Err() != nil {
+			results[i] = BatchResult{ID: entry.ID, Key: key, Name: entry.Data.Name, Email: entry.Data.Email, Status: BatchStatusSkipped, Error: "interrupted"}
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// submitBatchEntry submits a single entry and records its outcome
+func submitBatchEntry(ctx context.Context, deps Dependencies, service *ApplicationService, entry BatchEntry, key string, state *BatchState, logger *Logger) BatchResult {
+	result := BatchResult{ID: entry.ID, Key: key, Name: entry.Data.Name, Email: entry.Data.Email}
+
+	submitCtx, cancel := context.WithTimeout(ctx, time.Duration(deps.Config().Timeout+10)*time.Second)
+	defer cancel()
+
+	resp, err := service.submitApplication(submitCtx, entry.Data)
+	switch {
+	case err != nil:
+		result.Status = BatchStatusFailed
+		result.Error = err.Error()
+	case !resp.Succeeded():
+		// Non-success responses are reported rather than returned as errors
+		result.Status = BatchStatusFailed
+		result.Error = fmt.Sprintf("application endpoint returned status %d", resp.StatusCode)
+	default:
+		result.Status = BatchStatusSubmitted
+	}
+	result.CompletedAt = time.Now()
+
+	// Interrupted entries are left out of the state so they are retried on resume
+	if ctx.Err() == nil || result.Status == BatchStatusSubmitted {
+		if err := state.Record(result); err != nil {
+			logger.Warn("Failed to record batch state", "id", entry.ID, "error", err)
+		}
+	}
+
+	return result
+}
+
+// WriteBatchReport writes results as CSV
+func WriteBatchReport(w io.Writer, results []BatchResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "name", "email", "status", "error"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := writer.Write([]string{result.ID, result.Name, result.Email, result.Status, result.Error}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// printBatchSummary prints a per-entry report and totals
+func printBatchSummary(results []BatchResult) {
+	counts := make(map[string]int)
+
+	fmt.Printf("\n📊 Batch results:\n")
+	for _, result := range results {
+		counts[result.Status]++
+
+		icon := "✅"
+		switch result.Status {
+		case BatchStatusFailed, BatchStatusInvalid:
+			icon = "❌"
+		case BatchStatusSkipped:
+			icon = "⏭️ "
+		}
+
+		fmt.Printf("   %s %-12s %-25s %-30s %s", icon, result.ID, result.Name, result.Email, result.Status)
+		if result.Error != "" {
+			fmt.Printf(" (%s)", result.Error)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("\n   submitted: %d, skipped: %d, failed: %d, invalid: %d\n",
+		counts[BatchStatusSubmitted], counts[BatchStatusSkipped], counts[BatchStatusFailed], counts[BatchStatusInvalid])
+}
+
+// defineBatchFlags defines the options of the batch command on flags
+func defineBatchFlags(flags *flag.FlagSet, options *BatchOptions) {
+	flags.StringVar(&options.Input, "input", "", "CSV file or directory of JSON data files")
+	flags.IntVar(&options.Concurrency, "concurrency", 2, "Maximum number of concurrent submissions")
+	flags.StringVar(&options.StateFile, "state", "", "State file used to resume an interrupted batch (default <input>.state.json)")
+	flags.StringVar(&options.ReportFile, "report", "", "Write a CSV result report to this file")
+}
+
+// parseBatchOptions parses the flags of the batch command
+func parseBatchOptions(args []string) (*BatchOptions, error) {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	options := &BatchOptions{}
+	defineBatchFlags(flags, options)
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if options.Input == "" {
+		return nil, fmt.Errorf("--input is required")
+	}
+	if options.StateFile == "" {
+		options.StateFile = strings.TrimSuffix(options.Input, string(filepath.Separator)) + ".state.json"
+	}
+
+	return options, nil
+}
+
+// runBatchCommand handles `batch --input applicants.csv|dir/`, rendering the
+// templates of each applicant with vars
+func runBatchCommand(deps Dependencies, args []string, vars map[string]string) error {
+	options, err := parseBatchOptions(args)
+	if err != nil {
+		return err
+	}
+
+	entries, err := LoadBatchEntries(options.Input)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📖 Loaded %d applicants from %s\n", len(entries), options.Input)
+
+	for i := range entries {
+		if entries[i].Data, err = RenderApplicationData(entries[i].Data, vars); err != nil {
+			return fmt.Errorf("%s: failed to render application data: %w", entries[i].ID, err)
+		}
+	}
+
+	// Validate everything before submitting anything
+	if invalid := validateBatchEntries(entries, deps.Config().Attachments.options()); len(invalid) > 0 {
+		printBatchSummary(invalid)
+		return fmt.Errorf("%d of %d applicants failed validation, nothing was submitted", len(invalid), len(entries))
+	}
+
+	if err := NewConfigService(deps).ValidateConfig(); err != nil {
+		return err
+	}
+
+	state, err := LoadBatchState(options.StateFile)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results := RunBatch(ctx, deps, entries, state, options.Concurrency)
+	printBatchSummary(results)
+
+	if options.ReportFile != "" {
+		file, err := os.Create(options.ReportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create batch report: %w", err)
+		}
+		defer file.Close()
+		if err := WriteBatchReport(file, results); err != nil {
+			return fmt.Errorf("failed to write batch report: %w", err)
+		}
+		fmt.Printf("📄 Report written to %s\n", options.ReportFile)
+	}
+
+	for _, result := range results {
+		if result.Status == BatchStatusFailed || (result.Status == BatchStatusSkipped && result.Error != "") {
+			return fmt.Errorf("batch incomplete, rerun with the same input to resume (state: %s)", options.StateFile)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"micv/client"
+)
+
+func TestParseBatchCSV(t *testing.T) {
+	content := `name,email,job_title,final_attempt,location,experience.years_of_experience
+John Doe,john@example.com,Software Engineer,true,Australia,5
+Jane Smith,jane@example.com,Backend Engineer,,,
+`
+
+	entries, err := parseBatchCSV(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("parseBatchCSV failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.ID != "row-2" || first.Data.Name != "John Doe" || first.Data.JobTitle != "Software Engineer" {
+		t.Errorf("Unexpected first entry: %+v", first)
+	}
+	if first.Data.FinalAttempt == nil || !*first.Data.FinalAttempt {
+		t.Error("Expected final_attempt to be true for first entry")
+	}
+
+	extra, ok := first.Data.ExtraInformation.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected extra information map, got %T", first.Data.ExtraInformation)
+	}
+	if extra["location"] != "Australia" {
+		t.Errorf("Expected location 'Australia', got %v", extra["location"])
+	}
+	experience, ok := extra["experience"].(map[string]interface{})
+	if !ok || experience["years_of_experience"] != "5" {
+		t.Errorf("Expected nested experience.years_of_experience, got %v", extra["experience"])
+	}
+
+	second := entries[1]
+	if second.Data.FinalAttempt != nil {
+		t.Error("Expected final_attempt to be unset for second entry")
+	}
+	if second.Data.ExtraInformation != nil {
+		t.Errorf("Expected no extra information for second entry, got %v", second.Data.ExtraInformation)
+	}
+}
+
+func TestParseBatchCSVFinalAttempt(t *testing.T) {
+	content := `name,email,job_title,final_attempt
+John Doe,john@example.com,Software Engineer,1
+Jane Smith,jane@example.com,Backend Engineer,FALSE
+`
+	entries, err := parseBatchCSV(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("parseBatchCSV failed: %v", err)
+	}
+	if entries[0].Data.FinalAttempt == nil || !*entries[0].Data.FinalAttempt {
+		t.Error("Expected final_attempt 1 to be true")
+	}
+	if entries[1].Data.FinalAttempt != nil {
+		t.Error("Expected final_attempt FALSE to be unset")
+	}
+
+	invalid := "name,email,job_title,final_attempt\nJohn Doe,john@example.com,Software Engineer,yes please\n"
+	if _, err := parseBatchCSV(strings.NewReader(invalid)); err == nil || !strings.Contains(err.Error(), "row 2") {
+		t.Errorf("Expected invalid final_attempt error for row 2, got %v", err)
+	}
+}
+
+func TestLoadBatchEntriesDirectory(t *testing.T) {
+	entries, err := LoadBatchEntries("testdata")
+	if err != nil {
+		t.Fatalf("LoadBatchEntries failed: %v", err)
+	}
+
+	// Directory entries are not validated at load time
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries from testdata, got %d", len(entries))
+	}
+
+	invalid := validateBatchEntries(entries, client.AttachmentOptions{})
+	if len(invalid) == 0 {
+		t.Error("Expected invalid entries from testdata to be reported")
+	}
+	for _, result := range invalid {
+		if result.Status != BatchStatusInvalid {
+			t.Errorf("Expected status %s, got %s", BatchStatusInvalid, result.Status)
+		}
+	}
+}
+
+func TestValidateBatchEntriesChecksAttachments(t *testing.T) {
+	dir := t.TempDir()
+	resume := filepath.Join(dir, "resume.pdf")
+	if err := os.WriteFile(resume, []byte("%PDF-1.7\n"+strings.Repeat("x", 2048)), 0644); err != nil {
+		t.Fatalf("Failed to write attachment: %v", err)
+	}
+
+	entries := []BatchEntry{
+		{ID: "ok.json", Data: ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Software Engineer"}},
+		{ID: "missing.json", Data: ApplicationData{Name: "Jane Smith", Email: "jane@example.com", JobTitle: "Backend Engineer",
+			Attachments: []Attachment{{Field: "resume", Path: filepath.Join(dir, "missing.pdf")}}}},
+		{ID: "large.json", Data: ApplicationData{Name: "Bob Brown", Email: "bob@example.com", JobTitle: "Frontend Engineer",
+			Attachments: []Attachment{{Field: "resume", Path: resume}}}},
+	}
+
+	invalid := validateBatchEntries(entries, client.AttachmentOptions{MaxFileSize: 1024})
+	if len(invalid) != 2 || invalid[0].ID != "missing.json" || invalid[1].ID != "large.json" {
+		t.Fatalf("Expected the missing and oversized attachments to be reported, got %+v", invalid)
+	}
+}
+
+func TestRunBatchResume(t *testing.T) {
+	entries := []BatchEntry{
+		{ID: "row-2", Data: ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Software Engineer"}},
+		{ID: "row-3", Data: ApplicationData{Name: "Jane Smith", Email: "jane@example.com", JobTitle: "Backend Engineer"}},
+		{ID: "row-4", Data: ApplicationData{Name: "Bob Brown", Email: "bob@example.com", JobTitle: "Frontend Engineer"}},
+	}
+
+	var submissions int32
+	failEmail := "jane@example.com"
+
+	deps := NewMockDependencies()
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&submissions, 1)
+		var body bytes.Buffer
+		body.ReadFrom(req.Body)
+		if failEmail != "" && strings.Contains(body.String(), failEmail) {
+			return nil, http.ErrServerClosed
+		}
+		return createResponse(200, `{"status":"success"}`), nil
+	}
+
+	statePath := filepath.Join(t.TempDir(), "batch.state.json")
+	state, err := LoadBatchState(statePath)
+	if err != nil {
+		t.Fatalf("LoadBatchState failed: %v", err)
+	}
+
+	results := RunBatch(context.Background(), deps, entries, state, 2)
+	statuses := map[string]string{}
+	for _, result := range results {
+		statuses[result.ID] = result.Status
+	}
+	if statuses["row-2"] != BatchStatusSubmitted || statuses["row-3"] != BatchStatusFailed || statuses["row-4"] != BatchStatusSubmitted {
+		t.Fatalf("Unexpected first run statuses: %v", statuses)
+	}
+
+	// Resume after the rows were reordered: only the failed applicant is
+	// submitted again, whatever its position
+	failEmail = ""
+	atomic.StoreInt32(&submissions, 0)
+	entries = []BatchEntry{
+		{ID: "row-2", Data: entries[2].Data},
+		{ID: "row-3", Data: entries[0].Data},
+		{ID: "row-4", Data: entries[1].Data},
+	}
+
+	state, err = LoadBatchState(statePath)
+	if err != nil {
+		t.Fatalf("LoadBatchState failed: %v", err)
+	}
+	results = RunBatch(context.Background(), deps, entries, state, 2)
+	emails := map[string]string{}
+	for _, result := range results {
+		statuses[result.ID] = result.Status
+		emails[result.ID] = result.Email
+	}
+	if statuses["row-2"] != BatchStatusSkipped || statuses["row-3"] != BatchStatusSkipped || statuses["row-4"] != BatchStatusSubmitted {
+		t.Errorf("Unexpected resumed statuses: %v", statuses)
+	}
+	if emails["row-4"] != "jane@example.com" {
+		t.Errorf("Expected the resubmitted row to be Jane's, got %v", emails)
+	}
+	if submissions != 1 {
+		t.Errorf("Expected 1 submission on resume, got %d", submissions)
+	}
+
+	var report bytes.Buffer
+	if err := WriteBatchReport(&report, results); err != nil {
+		t.Fatalf("WriteBatchReport failed: %v", err)
+	}
+	if !strings.HasPrefix(report.String(), "id,name,email,status,error\n") || strings.Count(report.String(), "\n") != 4 {
+		t.Errorf("Unexpected report:\n%s", report.String())
+	}
+}
+
+func TestRunBatchNonSuccessStatus(t *testing.T) {
+	entries := []BatchEntry{
+		{ID: "row-2", Data: ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Software Engineer"}},
+	}
+
+	status := http.StatusInternalServerError
+	var submissions int32
+	deps := NewMockDependencies()
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&submissions, 1)
+		return createResponse(status, `{}`), nil
+	}
+
+	statePath := filepath.Join(t.TempDir(), "batch.state.json")
+	state, err := LoadBatchState(statePath)
+	if err != nil {
+		t.Fatalf("LoadBatchState failed: %v", err)
+	}
+
+	results := RunBatch(context.Background(), deps, entries, state, 1)
+	if results[0].Status != BatchStatusFailed || !strings.Contains(results[0].Error, "500") {
+		t.Fatalf("Expected a 500 response to fail the entry, got %+v", results[0])
+	}
+
+	// Resume submits the failed entry again
+	status = http.StatusOK
+	atomic.StoreInt32(&submissions, 0)
+	if state, err = LoadBatchState(statePath); err != nil {
+		t.Fatalf("LoadBatchState failed: %v", err)
+	}
+	results = RunBatch(context.Background(), deps, entries, state, 1)
+	if results[0].Status != BatchStatusSubmitted || submissions != 1 {
+		t.Errorf("Expected the entry to be submitted on resume, got %+v after %d submissions", results[0], submissions)
+	}
+}
+
+func TestParseBatchOptions(t *testing.T) {
+	options, err := parseBatchOptions([]string{"--input", "applicants/", "--concurrency", "4"})
+	if err != nil {
+		t.Fatalf("parseBatchOptions failed: %v", err)
+	}
+	if options.Concurrency != 4 {
+		t.Errorf("Expected concurrency 4, got %d", options.Concurrency)
+	}
+	if options.StateFile != "applicants.state.json" {
+		t.Errorf("Expected default state file 'applicants.state.json', got %s", options.StateFile)
+	}
+
+	if _, err := parseBatchOptions([]string{}); err == nil {
+		t.Error("Expected error when --input is missing")
+	}
+}
+
+func TestBatchStateMissingFile(t *testing.T) {
+	state, err := LoadBatchState(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected missing state file to be treated as empty, got: %v", err)
+	}
+	if _, ok := state.Submitted("key"); ok {
+		t.Error("Expected empty state")
+	}
+
+	if err := os.WriteFile(state.path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+	if _, err := LoadBatchState(state.path); err == nil {
+		t.Error("Expected error for corrupt state file")
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// cassetteFileName is the name of the cassette file inside a record/replay directory
+const cassetteFileName = "cassette.json"
+
+// redactedValue replaces secrets in recorded interactions
+const redactedValue = "REDACTED"
+
+// Cassette holds a sequence of recorded HTTP interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction represents a single recorded request/response pair
+type Interaction struct {
+	Request  RecordedRequest   `json:"request"`
+	Response *RecordedResponse `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// RecordedRequest represents the recorded part of an outgoing request
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse represents the recorded part of a received response
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// cassettePath returns the cassette file path for a record/replay directory
+func cassettePath(dir string) string {
+	return filepath.Join(dir, cassetteFileName)
+}
+
+// LoadCassette loads a cassette from a record/replay directory
+func LoadCassette(dir string) (*Cassette, error) {
+	data, err := os.ReadFile(cassettePath(dir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette: %w", err)
+	}
+
+	return &cassette, nil
+}
+
+// RecordingClient decorates an HTTPClient and saves every interaction to disk.
+// Tokens returned by the secret endpoint are redacted wherever they appear,
+// whatever authorization scheme sends them.
+type RecordingClient struct {
+	next      HTTPClient
+	dir       string
+	secretURL string
+	extractor SecretExtractor
+	mu        sync.Mutex
+	cassette  Cassette
+	secrets   []string
+}
+
+// NewRecordingClient creates a client that records interactions into dir,
+// using extractor to find the token in responses from secretURL
+func NewRecordingClient(next HTTPClient, dir, secretURL string, extractor SecretExtractor) (*RecordingClient, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+
+	return &RecordingClient{next: next, dir: dir, secretURL: secretURL, extractor: extractor}, nil
+}
+
+func (r *RecordingClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.Do(req)
+}
+
+func (r *RecordingClient) Do(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+			Body:   string(reqBody),
+		},
+	}
+
+	resp, err := r.next.Do(req)
+	if err != nil {
+		interaction.Error = err.Error()
+	} else {
+		respBody, readErr := drainBody(&resp.Body)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", readErr)
+		}
+		interaction.Response = &RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header.Clone(),
+			Body:       string(respBody),
+		}
+	}
+
+	if saveErr := r.record(interaction); saveErr != nil {
+		return nil, saveErr
+	}
+
+	return resp, err
+}
+
+// record appends an interaction and rewrites the redacted cassette
+func (r *RecordingClient) record(interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if auth := interaction.Request.Header.Get("Authorization"); auth != "" {
+		r.secrets = append(r.secrets, auth)
+	}
+	if r.isSecretRequest(interaction.Request) && interaction.Response != nil {
+		if token, ok := r.extractToken(interaction.Response); ok {
+			r.secrets = append(r.secrets, token)
+		} else {
+			// Without a token to look for, keep nothing of the response
+			response := *interaction.Response
+			response.Header = redactHeader(response.Header, func(string) string { return redactedValue })
+			response.Body = redactedValue
+			interaction.Response = &response
+		}
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+
+	redacted := Cassette{Interactions: make([]Interaction, len(r.cassette.Interactions))}
+	for i, recorded := range r.cassette.Interactions {
+		redacted.Interactions[i] = redactInteraction(recorded, r.secrets)
+	}
+
+	data, err := json.MarshalIndent(redacted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.WriteFile(cassettePath(r.dir), data, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// isSecretRequest reports whether request was sent to the secret endpoint
+func (r *RecordingClient) isSecretRequest(request RecordedRequest) bool {
+	if r.secretURL == "" {
+		return false
+	}
+	secretURL, err := url.Parse(r.secretURL)
+	if err != nil {
+		return request.URL == r.secretURL
+	}
+	return request.URL == secretURL.String()
+}
+
+// extractToken extracts the token from a recorded secret endpoint response
+func (r *RecordingClient) extractToken(response *RecordedResponse) (string, bool) {
+	if r.extractor == nil || response.StatusCode != http.StatusOK {
+		return "", false
+	}
+
+	token, err := r.extractor.Extract(&http.Response{
+		StatusCode: response.StatusCode,
+		Header:     response.Header.Clone(),
+		Body:       io.NopCloser(strings.NewReader(response.Body)),
+	})
+	if err != nil || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// redactInteraction removes known secrets from headers and bodies
+func redactInteraction(interaction Interaction, secrets []string) Interaction {
+	redact := func(value string) string {
+		for _, secret := range secrets {
+			value = strings.ReplaceAll(value, secret, redactedValue)
+		}
+		return value
+	}
+
+	redacted := interaction
+	redacted.Request.Header = redactHeader(interaction.Request.Header, redact)
+	redacted.Request.Body = redact(interaction.Request.Body)
+	redacted.Error = redact(interaction.Error)
+	if interaction.Response != nil {
+		response := *interaction.Response
+		response.Header = redactHeader(response.Header, redact)
+		response.Body = redact(response.Body)
+		redacted.Response = &response
+	}
+
+	return redacted
+}
+
+// redactHeader applies redact to every header value
+func redactHeader(header http.Header, redact func(string) string) http.Header {
+	if header == nil {
+		return nil
+	}
+
+	redacted := make(http.Header, len(header))
+	for key, values := range header {
+		for _, value := range values {
+			redacted.Add(key, redact(value))
+		}
+	}
+	return redacted
+}
+
+// drainBody reads a body fully and replaces it with an in-memory copy
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// ReplayClient serves recorded interactions without network access
+type ReplayClient struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayClient creates a client that replays the cassette stored in dir
+func NewReplayClient(dir string) (*ReplayClient, error) {
+	cassette, err := LoadCassette(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayClient{
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}, nil
+}
+
+func (r *ReplayClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.Do(req)
+}
+
+func (r *ReplayClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url := req.URL.String()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != url {
+			continue
+		}
+		r.used[i] = true
+
+		if interaction.Response == nil {
+			return nil, errors.New(interaction.Error)
+		}
+
+		return &http.Response{
+			StatusCode: interaction.Response.StatusCode,
+			Status:     interaction.Response.Status,
+			Header:     interaction.Response.Header.Clone(),
+			Body:       io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, url)
+}
+
+// newCassetteClient wraps client for record or replay mode when requested
+func newCassetteClient(configResult *ConfigResult, client HTTPClient) (HTTPClient, error) {
+	switch {
+	case configResult.RecordDir != "" && configResult.ReplayDir != "":
+		return nil, fmt.Errorf("cannot use --record and --replay together")
+	case configResult.RecordDir != "":
+		extractor, err := NewSecretExtractor(configResult.Config.SecretResponse)
+		if err != nil {
+			return nil, err
+		}
+		return NewRecordingClient(client, configResult.RecordDir, configResult.Config.SecretURL, extractor)
+	case configResult.ReplayDir != "":
+		return NewReplayClient(configResult.ReplayDir)
+	default:
+		return client, nil
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/secret":
+			w.Write([]byte(`{"result":"super-secret-token"}`))
+		case "/apply":
+			if r.Header.Get("Authorization") != "super-secret-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"status":"success"}`))
+		}
+	}))
+
+	dir := t.TempDir()
+	appData := ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	}
+
+	// Record a full submission against the live server
+	deps := NewMockDependencies()
+	deps.config.SecretURL = server.URL + "/secret"
+	deps.config.ApplicationURL = server.URL + "/apply"
+
+	recorder, err := NewRecordingClient(NewHTTPClientWithTimeout(5*time.Second), dir, deps.config.SecretURL, JSONSecretExtractor{})
+	if err != nil {
+		t.Fatalf("NewRecordingClient failed: %v", err)
+	}
+	recordDeps := &cassetteDependencies{MockDependencies: deps, client: recorder}
+
+	if err := NewApplicationService(recordDeps).SubmitApplication(context.Background(), appData); err != nil {
+		t.Fatalf("Recorded submission failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cassettePath(dir))
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(data), "super-secret-token") {
+		t.Errorf("Expected token to be redacted from cassette, got: %s", data)
+	}
+
+	cassette, err := LoadCassette(dir)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	if len(cassette.Interactions) != 2 {
+		t.Fatalf("Expected 2 recorded interactions, got %d", len(cassette.Interactions))
+	}
+
+	// Replay with the server gone
+	server.Close()
+
+	replayer, err := NewReplayClient(dir)
+	if err != nil {
+		t.Fatalf("NewReplayClient failed: %v", err)
+	}
+	replayDeps := &cassetteDependencies{MockDependencies: deps, client: replayer}
+
+	if err := NewApplicationService(replayDeps).SubmitApplication(context.Background(), appData); err != nil {
+		t.Errorf("Replayed submission failed: %v", err)
+	}
+}
+
+func TestRecordingRedactsTokenForAuthSchemes(t *testing.T) {
+	const token = "live-token-4f2a"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/secret":
+			w.Write([]byte(`{"result":"` + token + `"}`))
+		case "/apply":
+			w.Write([]byte(`{"status":"success"}`))
+		}
+	}))
+	defer server.Close()
+
+	authenticators := map[string]Authenticator{
+		AuthSchemeBearer: BearerAuthenticator{},
+		AuthSchemeHMAC:   HMACAuthenticator{Key: []byte("signing-key")},
+	}
+
+	for scheme, authenticator := range authenticators {
+		t.Run(scheme, func(t *testing.T) {
+			dir := t.TempDir()
+			deps := NewMockDependencies()
+			deps.config.SecretURL = server.URL + "/secret"
+			deps.config.ApplicationURL = server.URL + "/apply"
+			deps.authenticator = authenticator
+
+			recorder, err := NewRecordingClient(NewHTTPClientWithTimeout(5*time.Second), dir, deps.config.SecretURL, JSONSecretExtractor{})
+			if err != nil {
+				t.Fatalf("NewRecordingClient failed: %v", err)
+			}
+
+			appData := ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Software Engineer"}
+			recordDeps := &cassetteDependencies{MockDependencies: deps, client: recorder}
+			if err := NewApplicationService(recordDeps).SubmitApplication(context.Background(), appData); err != nil {
+				t.Fatalf("Recorded submission failed: %v", err)
+			}
+
+			data, err := os.ReadFile(cassettePath(dir))
+			if err != nil {
+				t.Fatalf("Failed to read cassette: %v", err)
+			}
+			if strings.Contains(string(data), token) {
+				t.Errorf("Expected token to be redacted from cassette, got: %s", data)
+			}
+		})
+	}
+}
+
+func TestRecordingRedactsTokenBeforeApplyRequest(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecordingClient(&MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"X-Token": []string{"header-token"}},
+				Body:       io.NopCloser(strings.NewReader(`{"data":{"token":"body-token"}}`)),
+			}, nil
+		},
+	}, dir, "https://example.com/secret", JSONSecretExtractor{Path: "data.token"})
+	if err != nil {
+		t.Fatalf("NewRecordingClient failed: %v", err)
+	}
+
+	// The run stops after fetching the token
+	if _, err := recorder.Get("https://example.com/secret"); err != nil {
+		t.Fatalf("Recorded request failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cassettePath(dir))
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(data), "body-token") {
+		t.Errorf("Expected token to be redacted from cassette, got: %s", data)
+	}
+
+	// Responses the extractor cannot read are redacted entirely
+	recorder.extractor = HeaderSecretExtractor{Header: "X-Missing"}
+	if _, err := recorder.Get("https://example.com/secret"); err != nil {
+		t.Fatalf("Recorded request failed: %v", err)
+	}
+
+	cassette, err := LoadCassette(dir)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	response := cassette.Interactions[1].Response
+	if response.Body != redactedValue || response.Header.Get("X-Token") != redactedValue {
+		t.Errorf("Expected unreadable secret response to be redacted, got: %+v", response)
+	}
+}
+
+func TestReplayClientErrors(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecordingClient(&MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return nil, http.ErrServerClosed
+		},
+	}, dir, "https://example.com/secret", JSONSecretExtractor{})
+	if err != nil {
+		t.Fatalf("NewRecordingClient failed: %v", err)
+	}
+
+	if _, err := recorder.Get("https://example.com/secret"); err == nil {
+		t.Fatal("Expected recorded network error")
+	}
+
+	replayer, err := NewReplayClient(dir)
+	if err != nil {
+		t.Fatalf("NewReplayClient failed: %v", err)
+	}
+
+	_, err = replayer.Get("https://example.com/secret")
+	if err == nil || !strings.Contains(err.Error(), http.ErrServerClosed.Error()) {
+		t.Errorf("Expected replayed network error, got: %v", err)
+	}
+
+	_, err = replayer.Get("https://example.com/secret")
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("Expected exhausted cassette error, got: %v", err)
+	}
+}
+
+func TestNewCassetteClientConflict(t *testing.T) {
+	_, err := newCassetteClient(&ConfigResult{RecordDir: "a", ReplayDir: "b"}, &MockHTTPClient{})
+	if err == nil {
+		t.Error("Expected error when both record and replay are set")
+	}
+}
+
+// cassetteDependencies overrides the HTTP client of MockDependencies
+type cassetteDependencies struct {
+	*MockDependencies
+	client HTTPClient
+}
+
+func (c *cassetteDependencies) HTTPClient() HTTPClient {
+	return c.client
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"micv/model"
+)
+
+// Default attachment settings
+const (
+	DefaultPayloadField       = "application"
+	DefaultMaxAttachmentSize  = 10 << 20
+	DefaultMaxAttachmentTotal = 25 << 20
+)
+
+// AttachmentOptions controls multipart submissions. Zero fields use the defaults.
+type AttachmentOptions struct {
+	// PayloadField is the form field of the JSON payload part
+	PayloadField string
+	// MaxFileSize and MaxTotalSize limit each file and all files, in bytes
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+// withDefaults fills in unset options
+func (o AttachmentOptions) withDefaults() AttachmentOptions {
+	if o.PayloadField == "" {
+		o.PayloadField = DefaultPayloadField
+	}
+	if o.MaxFileSize <= 0 {
+		o.MaxFileSize = DefaultMaxAttachmentSize
+	}
+	if o.MaxTotalSize <= 0 {
+		o.MaxTotalSize = DefaultMaxAttachmentTotal
+	}
+	return o
+}
+
+// WithAttachmentOptions sets the payload field and size limits of multipart submissions
+func WithAttachmentOptions(opts AttachmentOptions) Option {
+	return func(c *Client) {
+		c.attachments = opts
+	}
+}
+
+// attachmentType is an accepted document type and the type detected from its content
+type attachmentType struct {
+	contentType string
+	sniffed     string
+}
+
+// attachmentTypes lists the accepted document types by file extension. Office
+// formats are zip or OLE containers, which content sniffing reports generically.
+var attachmentTypes = map[string]attachmentType{
+	".pdf":  {"application/pdf", "application/pdf"},
+	".doc":  {"application/msword", "application/octet-stream"},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip"},
+	".odt":  {"application/vnd.oasis.opendocument.text", "application/zip"},
+	".rtf":  {"application/rtf", "text/plain"},
+	".txt":  {"text/plain", "text/plain"},
+}
+
+// CheckAttachments checks that every attachment is a readable file of an
+// accepted type whose content matches its type, within the size limits
+func CheckAttachments(attachments []model.Attachment, opts AttachmentOptions) error {
+	opts = opts.withDefaults()
+
+	var total int64
+	for _, attachment := range attachments {
+		size, err := checkAttachment(attachment, opts)
+		if err != nil {
+			return fmt.Errorf("attachment %q (%s): %w", attachment.Field, attachment.Path, err)
+		}
+		total += size
+	}
+	if total > opts.MaxTotalSize {
+		return fmt.Errorf("attachments total %d bytes, more than the %d byte limit", total, opts.MaxTotalSize)
+	}
+	return nil
+}
+
+// checkAttachment checks a single attachment and returns its size
+func checkAttachment(attachment model.Attachment, opts AttachmentOptions) (int64, error) {
+	if attachment.Field == "" || attachment.Path == "" {
+		return 0, fmt.Errorf("field and path are required")
+	}
+	if attachment.Field == opts.PayloadField {
+		return 0, fmt.Errorf("field is reserved for the application payload")
+	}
+
+	info, err := os.Stat(attachment.Path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return 0, fmt.Errorf("not a regular file")
+	}
+	if info.Size() > opts.MaxFileSize {
+		return 0, fmt.Errorf("file is %d bytes, more than the %d byte limit", info.Size(), opts.MaxFileSize)
+	}
+
+	expected, err := resolveAttachmentType(attachment)
+	if err != nil {
+		return 0, err
+	}
+	sniffed, err := sniffContentType(attachment.Path)
+	if err != nil {
+		return 0, err
+	}
+	if sniffed != expected.sniffed {
+		return 0, fmt.Errorf("content looks like %s, not %s", sniffed, expected.contentType)
+	}
+	return info.Size(), nil
+}
+
+// resolveAttachmentType returns the declared type, or the type implied by the file extension
+func resolveAttachmentType(attachment model.Attachment) (attachmentType, error) {
+	if attachment.ContentType == "" {
+		if t, ok := attachmentTypes[strings.ToLower(filepath.Ext(attachment.Path))]; ok {
+			return t, nil
+		}
+		return attachmentType{}, fmt.Errorf("unsupported file type %q", filepath.Ext(attachment.Path))
+	}
+
+	mediaType, _, err := mime.ParseMediaType(attachment.ContentType)
+	if err != nil {
+		return attachmentType{}, fmt.Errorf("invalid content type: %w", err)
+	}
+	for _, t := range attachmentTypes {
+		if t.contentType == mediaType {
+			return t, nil
+		}
+	}
+	return attachmentType{}, fmt.Errorf("unsupported content type %q", mediaType)
+}
+
+// sniffContentType detects the media type of a file from its first bytes
+func sniffContentType(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	return mediaType, nil
+}
+
+// buildMultipartBody encodes the JSON payload and the attachments as
+// multipart/form-data and returns the body and its content type
+func (c *Client) buildMultipartBody(jsonData []byte, attachments []model.Attachment) ([]byte, string, error) {
+	opts := c.attachments.withDefaults()
+	if err := CheckAttachments(attachments, opts); err != nil {
+		return nil, "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": opts.PayloadField}))
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(jsonData); err != nil {
+		return nil, "", err
+	}
+
+	for _, attachment := range attachments {
+		if err := c.writeAttachment(writer, attachment); err != nil {
+			return nil, "", fmt.Errorf("attachment %q (%s): %w", attachment.Field, attachment.Path, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+// writeAttachment adds one file part to the multipart body
+func (c *Client) writeAttachment(writer *multipart.Writer, attachment model.Attachment) error {
+	t, err := resolveAttachmentType(attachment)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(attachment.Path)
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Base(attachment.Path)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     attachment.Field,
+		"filename": filename,
+	}))
+	header.Set("Content-Type", t.contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.out, "📎 Attaching %s: %s (%s, %d bytes)\n", attachment.Field, filename, t.contentType, len(data))
+	return nil
+}
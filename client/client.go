@@ -0,0 +1,287 @@
+// Package client submits job applications to the MiTimes careers portal.
+//
+// A Client fetches an authorization token from the secret endpoint and posts
+// the application to the application endpoint, retrying failed requests and
+// optionally guarding token fetches with a circuit breaker:
+//
+//	c := client.New(secretURL, applicationURL,
+//		client.WithAuthenticator(client.BearerAuthenticator{}),
+//		client.WithRetry(resilience.DefaultRetryConfig()),
+//	)
+//	if err := c.Validate(data); err != nil {
+//		return err
+//	}
+//	token, err := c.FetchToken(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	resp, err := c.Submit(ctx, token, data)
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"micv/model"
+	"micv/resilience"
+	"micv/trace"
+	"micv/validate"
+)
+
+// DefaultTimeout is the request timeout of the default HTTP client
+const DefaultTimeout = 30 * time.Second
+
+// Client talks to the secret and application endpoints
+type Client struct {
+	secretURL      string
+	applicationURL string
+	httpClient     HTTPClient
+	auth           Authenticator
+	extractor      SecretExtractor
+	retry          resilience.RetryConfig
+	breaker        *resilience.CircuitBreaker
+	logger         resilience.Logger
+	out            io.Writer
+	hooks          []SubmitHook
+	observer       Observer
+	attachments    AttachmentOptions
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for all requests
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAuthenticator sets how application requests are authorized (default RawTokenAuthenticator)
+func WithAuthenticator(auth Authenticator) Option {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
+// WithSecretExtractor sets how the token is read from the secret response (default JSONSecretExtractor)
+func WithSecretExtractor(extractor SecretExtractor) Option {
+	return func(c *Client) {
+		c.extractor = extractor
+	}
+}
+
+// WithRetry sets the retry behaviour of FetchToken and Submit
+func WithRetry(retry resilience.RetryConfig) Option {
+	return func(c *Client) {
+		c.retry = retry
+	}
+}
+
+// WithCircuitBreaker guards FetchToken with the given circuit breaker
+func WithCircuitBreaker(breaker *resilience.CircuitBreaker) Option {
+	return func(c *Client) {
+		c.breaker = breaker
+	}
+}
+
+// WithLogger sets the structured logger (default discards messages)
+func WithLogger(logger resilience.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithOutput writes human-readable request and response progress to w (default discards it)
+func WithOutput(w io.Writer) Option {
+	return func(c *Client) {
+		c.out = w
+	}
+}
+
+// New creates a Client for the given secret and application endpoints
+func New(secretURL, applicationURL string, opts ...Option) *Client {
+	c := &Client{
+		secretURL:      secretURL,
+		applicationURL: applicationURL,
+		httpClient:     &http.Client{Timeout: DefaultTimeout},
+		auth:           RawTokenAuthenticator{},
+		extractor:      JSONSecretExtractor{},
+		retry:          resilience.DefaultRetryConfig(),
+		logger:         resilience.NopLogger{},
+		out:            io.Discard,
+		observer:       NopObserver{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Validate checks the application data and its attachments without sending it
+func (c *Client) Validate(data model.ApplicationData) error {
+	if err := validate.ApplicationData(data).Error; err != nil {
+		return err
+	}
+	return CheckAttachments(data.Attachments, c.attachments)
+}
+
+// FetchToken returns the static token of the authenticator, or fetches one from
+// the secret endpoint with retries behind the circuit breaker
+func (c *Client) FetchToken(ctx context.Context) (string, error) {
+	if token, ok := c.auth.StaticToken(); ok {
+		return token, nil
+	}
+
+	start := time.Now()
+	var token string
+	attempt := 0
+	fetch := func() error {
+		return resilience.WithRetry(ctx, c.retry, c.logger, func() error {
+			attempt++
+			attemptCtx, span := trace.Start(ctx, OperationFetchToken+".attempt")
+			span.SetAttribute("attempt", attempt)
+			span.SetAttribute("http.url", c.secretURL)
+			defer span.End()
+
+			attemptStart := time.Now()
+			var err error
+			token, err = c.fetchTokenOnce(attemptCtx)
+			c.observer.Attempt(OperationFetchToken, time.Since(attemptStart), err)
+			span.RecordError(err)
+			if err != nil {
+				c.logger.Debug("Token fetch attempt failed", "error", err)
+				return model.WrapAuthError(err, c.secretURL)
+			}
+			return nil
+		})
+	}
+
+	var err error
+	if c.breaker != nil {
+		err = c.breaker.Call(ctx, fetch)
+	} else {
+		err = fetch()
+	}
+	c.observer.TokenFetched(time.Since(start), err)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// SubmitOption configures a single submission
+type SubmitOption func(*submitSettings)
+
+// submitSettings holds the per-submission options
+type submitSettings struct {
+	idempotencyKey string
+	refreshToken   func(ctx context.Context) (string, error)
+}
+
+// WithIdempotencyKey sends key in the Idempotency-Key header on every attempt
+func WithIdempotencyKey(key string) SubmitOption {
+	return func(s *submitSettings) {
+		s.idempotencyKey = key
+	}
+}
+
+// WithTokenRefresh retries a submission whose token was rejected once, with the
+// token returned by refresh. Hooks still run only once around the submission.
+func WithTokenRefresh(refresh func(ctx context.Context) (string, error)) SubmitOption {
+	return func(s *submitSettings) {
+		s.refreshToken = refresh
+	}
+}
+
+// Submit posts the application with retries. A rejected token is not retried
+// unless WithTokenRefresh is given, and is reported as an error wrapping
+// ErrTokenRejected. Hooks run before the first attempt and after the last one.
+func (c *Client) Submit(ctx context.Context, token string, data model.ApplicationData, opts ...SubmitOption) (*SubmitResponse, error) {
+	var settings submitSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	if err := CheckAttachments(data.Attachments, c.attachments); err != nil {
+		return nil, model.WrapValidationError(err, "attachments")
+	}
+
+	var payload []byte
+	if len(c.hooks) > 0 {
+		var err error
+		if payload, err = json.MarshalIndent(payloadData(data), "", "  "); err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		for _, hook := range c.hooks {
+			if err := hook.BeforeSubmit(ctx, payload); err != nil {
+				c.logger.Warn("Submission vetoed by hook", "error", err)
+				return nil, model.NewAppError(model.ErrCodeApplication, "Submission vetoed by pre-submit hook",
+					fmt.Errorf("%w: %w", ErrSubmissionVetoed, err))
+			}
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.submitWithRetry(ctx, token, data, opts)
+	if errors.Is(err, ErrTokenRejected) && settings.refreshToken != nil {
+		var refreshErr error
+		if token, refreshErr = settings.refreshToken(ctx); refreshErr != nil {
+			err = refreshErr
+		} else {
+			resp, err = c.submitWithRetry(ctx, token, data, opts)
+		}
+	}
+	c.observer.Submitted(time.Since(start), resp, err)
+
+	result := SubmitResult{
+		ApplicationURL: c.applicationURL,
+		IdempotencyKey: settings.idempotencyKey,
+		Payload:        payload,
+		Response:       resp,
+		Duration:       time.Since(start),
+		Err:            err,
+	}
+	for _, hook := range c.hooks {
+		hook.AfterSubmit(ctx, result)
+	}
+	return resp, err
+}
+
+// submitWithRetry posts the application with retries using a single token
+func (c *Client) submitWithRetry(ctx context.Context, token string, data model.ApplicationData, opts []SubmitOption) (*SubmitResponse, error) {
+	var resp *SubmitResponse
+	attempt := 0
+	err := resilience.WithRetry(ctx, c.retry, c.logger, func() error {
+		attempt++
+		attemptCtx, span := trace.Start(ctx, OperationSubmit+".attempt")
+		span.SetAttribute("attempt", attempt)
+		span.SetAttribute("http.url", c.applicationURL)
+		defer span.End()
+
+		attemptStart := time.Now()
+		var err error
+		resp, err = c.SubmitOnce(attemptCtx, token, data, opts...)
+		c.observer.Attempt(OperationSubmit, time.Since(attemptStart), err)
+		span.RecordError(err)
+		if resp != nil {
+			span.SetAttribute("http.status_code", resp.StatusCode)
+		}
+
+		if errors.Is(err, ErrTokenRejected) {
+			c.logger.Debug("Application endpoint rejected the token", "error", err)
+			return resilience.Permanent(model.WrapAuthError(err, c.applicationURL))
+		}
+		if err != nil {
+			c.logger.Debug("Application submission attempt failed", "error", err)
+			return model.WrapNetworkError(err, c.applicationURL)
+		}
+		return nil
+	})
+	return resp, err
+}
@@ -0,0 +1,387 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"micv/model"
+	"micv/resilience"
+)
+
+// fastRetry retries quickly so that tests do not wait on backoff
+var fastRetry = resilience.RetryConfig{
+	MaxAttempts:  3,
+	InitialDelay: time.Millisecond,
+	MaxDelay:     time.Millisecond,
+	Multiplier:   1,
+}
+
+func testApplication() model.ApplicationData {
+	return model.ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	}
+}
+
+func TestClientFetchTokenAndSubmit(t *testing.T) {
+	var secretCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/secret":
+			// The first request fails so that FetchToken has to retry
+			if secretCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"data": {"token": "abc123"}}`))
+		case "/apply":
+			if got := r.Header.Get("Authorization"); got != "Bearer abc123" {
+				t.Errorf("Expected bearer token, got %q", got)
+			}
+			if got := r.Header.Get("Idempotency-Key"); got != "key-1" {
+				t.Errorf("Expected idempotency key, got %q", got)
+			}
+			var data model.ApplicationData
+			if err := json.NewDecoder(r.Body).Decode(&data); err != nil || data.Name != "John Doe" {
+				t.Errorf("Unexpected payload %+v: %v", data, err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"status": "received"}`))
+		}
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	c := New(server.URL+"/secret", server.URL+"/apply",
+		WithAuthenticator(BearerAuthenticator{}),
+		WithSecretExtractor(JSONSecretExtractor{Path: "data.token"}),
+		WithRetry(fastRetry),
+		WithOutput(&out),
+	)
+
+	ctx := context.Background()
+	token, err := c.FetchToken(ctx)
+	if err != nil {
+		t.Fatalf("FetchToken failed: %v", err)
+	}
+	if token != "abc123" || secretCalls.Load() != 2 {
+		t.Errorf("Expected token after one retry, got %q after %d calls", token, secretCalls.Load())
+	}
+
+	resp, err := c.Submit(ctx, token, testApplication(), WithIdempotencyKey("key-1"))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if !resp.Succeeded() || resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201 response, got %+v", resp)
+	}
+	if !strings.Contains(out.String(), "Application submitted successfully") {
+		t.Errorf("Expected progress output, got %q", out.String())
+	}
+}
+
+func TestClientSubmitTokenRejected(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := New("", server.URL, WithRetry(fastRetry))
+	_, err := c.Submit(context.Background(), "stale", testApplication())
+	if !errors.Is(err, ErrTokenRejected) {
+		t.Errorf("Expected ErrTokenRejected, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a rejected token not to be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestClientFetchTokenStatic(t *testing.T) {
+	c := New("http://secret.invalid", "", WithAuthenticator(StaticTokenAuthenticator{Token: "static"}))
+	token, err := c.FetchToken(context.Background())
+	if err != nil || token != "static" {
+		t.Errorf("Expected static token, got %q, %v", token, err)
+	}
+}
+
+func TestClientFetchTokenCircuitBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	breaker := resilience.NewCircuitBreaker(1, time.Minute, resilience.NopLogger{})
+	c := New(server.URL, "", WithRetry(resilience.RetryConfig{MaxAttempts: 1}), WithCircuitBreaker(breaker))
+
+	if _, err := c.FetchToken(context.Background()); err == nil {
+		t.Fatal("Expected token fetch to fail")
+	}
+	_, err := c.FetchToken(context.Background())
+	var appErr *model.AppError
+	if !errors.As(err, &appErr) || appErr.Code != model.ErrCodeTimeout {
+		t.Errorf("Expected open circuit breaker error, got %v", err)
+	}
+}
+
+func TestClientValidate(t *testing.T) {
+	c := New("", "")
+	if err := c.Validate(testApplication()); err != nil {
+		t.Errorf("Expected valid application, got %v", err)
+	}
+
+	data := testApplication()
+	data.Email = "not-an-email"
+	if err := c.Validate(data); err == nil {
+		t.Error("Expected invalid email to fail validation")
+	}
+}
+
+func TestClientSubmitHooks(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"status": "received"}`))
+	}))
+	defer server.Close()
+
+	var before []byte
+	var after SubmitResult
+	hook := HookFuncs{
+		Before: func(ctx context.Context, payload []byte) error {
+			before = payload
+			return nil
+		},
+		After: func(ctx context.Context, result SubmitResult) {
+			after = result
+		},
+	}
+	c := New("", server.URL, WithRetry(fastRetry), WithHooks(hook))
+
+	if _, err := c.Submit(context.Background(), "token", testApplication(), WithIdempotencyKey("key-1")); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if !strings.Contains(string(before), `"name": "John Doe"`) {
+		t.Errorf("Expected payload in pre-submit hook, got %s", before)
+	}
+	if after.Response == nil || after.Response.StatusCode != http.StatusOK || after.Err != nil {
+		t.Errorf("Expected successful result in post-submit hook, got %+v", after)
+	}
+	if after.IdempotencyKey != "key-1" || after.ApplicationURL != server.URL || after.Duration <= 0 {
+		t.Errorf("Unexpected post-submit result %+v", after)
+	}
+
+	// A veto stops the request and skips the post-submit hook
+	after = SubmitResult{}
+	veto := HookFuncs{Before: func(ctx context.Context, payload []byte) error {
+		return errors.New("policy check failed")
+	}}
+	c = New("", server.URL, WithRetry(fastRetry), WithHooks(veto, hook))
+	_, err := c.Submit(context.Background(), "token", testApplication())
+	if !errors.Is(err, ErrSubmissionVetoed) || !strings.Contains(err.Error(), "policy check failed") {
+		t.Errorf("Expected vetoed submission, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected vetoed submission not to be sent, got %d requests", calls.Load())
+	}
+	if after.Response != nil {
+		t.Error("Expected post-submit hook not to run after a veto")
+	}
+}
+
+// recordingObserver records the observations of a client
+type recordingObserver struct {
+	attempts     []string
+	tokenErr     error
+	tokenFetches int
+	submitStatus int
+	submissions  int
+}
+
+func (o *recordingObserver) Attempt(operation string, duration time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	o.attempts = append(o.attempts, operation+":"+outcome)
+}
+
+func (o *recordingObserver) TokenFetched(duration time.Duration, err error) {
+	o.tokenFetches++
+	o.tokenErr = err
+}
+
+func (o *recordingObserver) Submitted(duration time.Duration, resp *SubmitResponse, err error) {
+	o.submissions++
+	if resp != nil {
+		o.submitStatus = resp.StatusCode
+	}
+}
+
+func TestClientObserver(t *testing.T) {
+	var secretCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/secret" {
+			if secretCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"result": "abc123"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	c := New(server.URL+"/secret", server.URL+"/apply", WithRetry(fastRetry), WithObserver(observer))
+
+	token, err := c.FetchToken(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if _, err := c.Submit(context.Background(), token, testApplication()); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expected := []string{"fetch_token:failure", "fetch_token:success", "submit:success"}
+	if strings.Join(observer.attempts, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected attempts %v, got %v", expected, observer.attempts)
+	}
+	if observer.tokenFetches != 1 || observer.tokenErr != nil {
+		t.Errorf("Expected one successful token fetch, got %d (%v)", observer.tokenFetches, observer.tokenErr)
+	}
+	if observer.submissions != 1 || observer.submitStatus != http.StatusCreated {
+		t.Errorf("Expected one submission with status 201, got %d (%d)", observer.submissions, observer.submitStatus)
+	}
+}
+
+// writeAttachmentFile writes content to name in dir and returns the path
+func writeAttachmentFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write attachment: %v", err)
+	}
+	return path
+}
+
+func TestClientSubmitAttachments(t *testing.T) {
+	dir := t.TempDir()
+	resume := writeAttachmentFile(t, dir, "resume.pdf", "%PDF-1.7\n...")
+	letter := writeAttachmentFile(t, dir, "cover-letter.txt", "Dear hiring manager")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("Expected multipart request: %v", err)
+		}
+
+		var data model.ApplicationData
+		if err := json.Unmarshal([]byte(r.FormValue("payload")), &data); err != nil || data.Name != "John Doe" {
+			t.Errorf("Unexpected payload %+v: %v", data, err)
+		}
+		if data.Attachments != nil {
+			t.Errorf("Expected attachments to be left out of the payload, got %+v", data.Attachments)
+		}
+
+		for field, expected := range map[string]string{"resume": "application/pdf", "cover_letter": "text/plain"} {
+			files := r.MultipartForm.File[field]
+			if len(files) != 1 || files[0].Header.Get("Content-Type") != expected {
+				t.Errorf("Expected one %s file for %s, got %+v", expected, field, files)
+			}
+		}
+		if files := r.MultipartForm.File["resume"]; len(files) == 1 && files[0].Filename != "resume.pdf" {
+			t.Errorf("Expected file name resume.pdf, got %s", files[0].Filename)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	data := testApplication()
+	data.Attachments = []model.Attachment{
+		{Field: "resume", Path: resume},
+		{Field: "cover_letter", Path: letter},
+	}
+	c := New("", server.URL, WithRetry(fastRetry), WithAttachmentOptions(AttachmentOptions{PayloadField: "payload"}))
+	if err := c.Validate(data); err != nil {
+		t.Fatalf("Expected valid attachments but got: %v", err)
+	}
+	resp, err := c.Submit(context.Background(), "abc123", data)
+	if err != nil || !resp.Succeeded() {
+		t.Fatalf("Expected successful submission, got %+v: %v", resp, err)
+	}
+}
+
+func TestCheckAttachments(t *testing.T) {
+	dir := t.TempDir()
+	pdf := writeAttachmentFile(t, dir, "resume.pdf", "%PDF-1.7\n...")
+	fakePDF := writeAttachmentFile(t, dir, "fake.pdf", "plain text")
+	text := writeAttachmentFile(t, dir, "letter.txt", strings.Repeat("a", 2048))
+	script := writeAttachmentFile(t, dir, "run.sh", "#!/bin/sh")
+
+	tests := []struct {
+		name        string
+		attachments []model.Attachment
+		opts        AttachmentOptions
+		expected    string
+	}{
+		{"valid", []model.Attachment{{Field: "resume", Path: pdf}, {Field: "letter", Path: text}}, AttachmentOptions{}, ""},
+		{"declared type", []model.Attachment{{Field: "letter", Path: text, ContentType: "text/plain; charset=utf-8"}}, AttachmentOptions{}, ""},
+		{"missing field", []model.Attachment{{Path: pdf}}, AttachmentOptions{}, "field and path are required"},
+		{"reserved field", []model.Attachment{{Field: "application", Path: pdf}}, AttachmentOptions{}, "reserved"},
+		{"missing file", []model.Attachment{{Field: "resume", Path: filepath.Join(dir, "missing.pdf")}}, AttachmentOptions{}, "no such file"},
+		{"directory", []model.Attachment{{Field: "resume", Path: dir}}, AttachmentOptions{}, "not a regular file"},
+		{"unsupported extension", []model.Attachment{{Field: "resume", Path: script}}, AttachmentOptions{}, "unsupported file type"},
+		{"unsupported type", []model.Attachment{{Field: "resume", Path: pdf, ContentType: "image/png"}}, AttachmentOptions{}, "unsupported content type"},
+		{"content mismatch", []model.Attachment{{Field: "resume", Path: fakePDF}}, AttachmentOptions{}, "content looks like text/plain"},
+		{"file too large", []model.Attachment{{Field: "letter", Path: text}}, AttachmentOptions{MaxFileSize: 1024}, "more than the 1024 byte limit"},
+		{"total too large", []model.Attachment{{Field: "a", Path: text}, {Field: "b", Path: text}}, AttachmentOptions{MaxTotalSize: 3000}, "attachments total 4096 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAttachments(tt.attachments, tt.opts)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestClientSubmitRejectsInvalidAttachments(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	data := testApplication()
+	data.Attachments = []model.Attachment{{Field: "resume", Path: filepath.Join(t.TempDir(), "missing.pdf")}}
+	_, err := New("", server.URL, WithRetry(fastRetry)).Submit(context.Background(), "abc123", data)
+
+	var appErr *model.AppError
+	if !errors.As(err, &appErr) || appErr.Code != model.ErrCodeValidation {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("Expected nothing to be sent, got %d requests", calls.Load())
+	}
+}
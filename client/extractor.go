@@ -0,0 +1,145 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultSecretPath is the JSON path of the token in the default secret response
+const DefaultSecretPath = "result"
+
+// SecretExtractor extracts the authorization token from a secret endpoint response
+type SecretExtractor interface {
+	Extract(resp *http.Response) (string, error)
+}
+
+// JSONSecretExtractor reads the token from a JSON body using a dotted path or JSON pointer
+type JSONSecretExtractor struct {
+	Path string
+}
+
+func (e JSONSecretExtractor) Extract(resp *http.Response) (string, error) {
+	body, err := readSecretBody(resp)
+	if err != nil {
+		return "", err
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return "", fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	path := e.Path
+	if path == "" {
+		path = DefaultSecretPath
+	}
+
+	value, ok := lookupJSONPath(document, path)
+	if !ok {
+		return "", fmt.Errorf("empty result in secret response")
+	}
+
+	token, err := jsonScalarString(value)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", fmt.Errorf("empty result in secret response")
+	}
+
+	return token, nil
+}
+
+// TextSecretExtractor uses the whole plain-text body as the token
+type TextSecretExtractor struct{}
+
+func (TextSecretExtractor) Extract(resp *http.Response) (string, error) {
+	body, err := readSecretBody(resp)
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(body))
+	if token == "" {
+		return "", fmt.Errorf("empty result in secret response")
+	}
+
+	return token, nil
+}
+
+// HeaderSecretExtractor reads the token from a response header
+type HeaderSecretExtractor struct {
+	Header string
+}
+
+func (e HeaderSecretExtractor) Extract(resp *http.Response) (string, error) {
+	token := strings.TrimSpace(resp.Header.Get(e.Header))
+	if token == "" {
+		return "", fmt.Errorf("empty %s header in secret response", e.Header)
+	}
+
+	return token, nil
+}
+
+// readSecretBody reads the secret endpoint response body
+func readSecretBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return body, nil
+}
+
+// lookupJSONPath resolves a dotted path ("data.token") or JSON pointer ("/data/token")
+func lookupJSONPath(document interface{}, path string) (interface{}, bool) {
+	var segments []string
+	if strings.HasPrefix(path, "/") {
+		for _, segment := range strings.Split(path[1:], "/") {
+			segment = strings.ReplaceAll(segment, "~1", "/")
+			segments = append(segments, strings.ReplaceAll(segment, "~0", "~"))
+		}
+	} else {
+		segments = strings.Split(path, ".")
+	}
+
+	current := document
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, current != nil
+}
+
+// jsonScalarString converts a JSON scalar to its string form
+func jsonScalarString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("secret response value is not a scalar")
+	}
+}
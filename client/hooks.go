@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSubmissionVetoed is wrapped by the error returned when a hook vetoes a submission
+var ErrSubmissionVetoed = errors.New("submission vetoed by hook")
+
+// SubmitHook runs before and after each call to Client.Submit
+type SubmitHook interface {
+	// BeforeSubmit receives the JSON payload about to be sent; an error vetoes the submission
+	BeforeSubmit(ctx context.Context, payload []byte) error
+	// AfterSubmit receives the outcome once all attempts have finished
+	AfterSubmit(ctx context.Context, result SubmitResult)
+}
+
+// SubmitResult describes a finished submission
+type SubmitResult struct {
+	ApplicationURL string
+	IdempotencyKey string
+	Payload        []byte
+	// Response is nil when no response was received
+	Response *SubmitResponse
+	Duration time.Duration
+	Err      error
+}
+
+// HookFuncs adapts plain functions to SubmitHook; nil functions are skipped
+type HookFuncs struct {
+	Before func(ctx context.Context, payload []byte) error
+	After  func(ctx context.Context, result SubmitResult)
+}
+
+// BeforeSubmit implements SubmitHook
+func (h HookFuncs) BeforeSubmit(ctx context.Context, payload []byte) error {
+	if h.Before == nil {
+		return nil
+	}
+	return h.Before(ctx, payload)
+}
+
+// AfterSubmit implements SubmitHook
+func (h HookFuncs) AfterSubmit(ctx context.Context, result SubmitResult) {
+	if h.After != nil {
+		h.After(ctx, result)
+	}
+}
+
+// WithHooks adds hooks that run around every submission, in order
+func WithHooks(hooks ...SubmitHook) Option {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hooks...)
+	}
+}
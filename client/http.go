@@ -0,0 +1,10 @@
+package client
+
+import "net/http"
+
+// HTTPClient is the subset of *http.Client used to talk to the endpoints,
+// so that requests can be decorated or mocked
+type HTTPClient interface {
+	Get(url string) (*http.Response, error)
+	Do(req *http.Request) (*http.Response, error)
+}
@@ -0,0 +1,34 @@
+package client
+
+import "time"
+
+// Operations reported to an Observer
+const (
+	OperationFetchToken = "fetch_token"
+	OperationSubmit     = "submit"
+)
+
+// Observer receives the duration and outcome of the requests a Client makes
+type Observer interface {
+	// Attempt is called after every attempt of operation, including retries
+	Attempt(operation string, duration time.Duration, err error)
+	// TokenFetched is called once FetchToken has finished all attempts
+	TokenFetched(duration time.Duration, err error)
+	// Submitted is called once Submit has finished all attempts; resp is nil
+	// when no response was received
+	Submitted(duration time.Duration, resp *SubmitResponse, err error)
+}
+
+// NopObserver ignores all observations
+type NopObserver struct{}
+
+func (NopObserver) Attempt(operation string, duration time.Duration, err error)       {}
+func (NopObserver) TokenFetched(duration time.Duration, err error)                    {}
+func (NopObserver) Submitted(duration time.Duration, resp *SubmitResponse, err error) {}
+
+// WithObserver reports request timings and outcomes to observer
+func WithObserver(observer Observer) Option {
+	return func(c *Client) {
+		c.observer = observer
+	}
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"micv/model"
+	"micv/trace"
+)
+
+// SubmitResponse holds the response to an application request
+type SubmitResponse struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+// Succeeded reports whether the application endpoint returned a 2xx status
+func (r *SubmitResponse) Succeeded() bool {
+	return r != nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// ErrTokenRejected is returned when the application endpoint rejects the authorization token
+var ErrTokenRejected = errors.New("authorization token rejected")
+
+// FetchTokenOnce makes a single request to the secret endpoint and extracts the token
+func (c *Client) FetchTokenOnce() (string, error) {
+	return c.fetchTokenOnce(context.Background())
+}
+
+// fetchTokenOnce makes a single request to the secret endpoint, propagating the trace of ctx
+func (c *Client) fetchTokenOnce(ctx context.Context) (string, error) {
+	// Make request to secret endpoint
+	resp, err := c.getSecret(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Validate response status
+	if err := c.validateSecretResponse(resp); err != nil {
+		return "", err
+	}
+
+	// Read the body once so that it can be shown before it is parsed
+	body, err := readSecretBody(resp)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(c.out, "📄 Secret endpoint response body: %s\n", string(body))
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return c.extractor.Extract(resp)
+}
+
+// getSecret requests the secret endpoint. Traced requests are sent with Do,
+// since Get cannot carry the traceparent header.
+func (c *Client) getSecret(ctx context.Context) (*http.Response, error) {
+	if trace.SpanFromContext(ctx) == nil {
+		return c.httpClient.Get(c.secretURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.secretURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	trace.Inject(ctx, req.Header)
+	return c.httpClient.Do(req)
+}
+
+// validateSecretResponse validates the HTTP response from secret endpoint
+func (c *Client) validateSecretResponse(resp *http.Response) error {
+	fmt.Fprintf(c.out, "🌐 Secret endpoint HTTP Status: %d %s\n", resp.StatusCode, resp.Status)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("secret endpoint returned non-success status: %d %s", resp.StatusCode, resp.Status)
+	}
+
+	return nil
+}
+
+// SubmitOnce makes a single application request and returns the response
+func (c *Client) SubmitOnce(ctx context.Context, token string, data model.ApplicationData, opts ...SubmitOption) (*SubmitResponse, error) {
+	var settings submitSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	// Prepare JSON data
+	jsonData, err := c.prepareApplicationJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create and send request
+	req, err := c.createApplicationRequest(ctx, token, jsonData, data.Attachments, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute request and handle response
+	return c.executeApplicationRequest(req)
+}
+
+// prepareApplicationJSON converts application data to JSON
+func (c *Client) prepareApplicationJSON(data model.ApplicationData) ([]byte, error) {
+	jsonData, err := json.MarshalIndent(payloadData(data), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	fmt.Fprintf(c.out, "📋 Application data being sent:\n%s\n", string(jsonData))
+	return jsonData, nil
+}
+
+// payloadData returns the data sent as JSON; attachments are sent as separate parts
+func payloadData(data model.ApplicationData) model.ApplicationData {
+	data.Attachments = nil
+	return data
+}
+
+// createApplicationRequest creates HTTP request for application submission,
+// as multipart/form-data when there are attachments
+func (c *Client) createApplicationRequest(ctx context.Context, token string, jsonData []byte, attachments []model.Attachment, settings submitSettings) (*http.Request, error) {
+	body, contentType := jsonData, "application/json"
+	if len(attachments) > 0 {
+		var err error
+		if body, contentType, err = c.buildMultipartBody(jsonData, attachments); err != nil {
+			return nil, fmt.Errorf("failed to build multipart request: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.applicationURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", contentType)
+	trace.Inject(ctx, req.Header)
+	if settings.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", settings.idempotencyKey)
+	}
+	if err := c.auth.Apply(req, token, body); err != nil {
+		return nil, fmt.Errorf("failed to authorize request: %w", err)
+	}
+
+	return req, nil
+}
+
+// executeApplicationRequest executes the application request and handles response
+func (c *Client) executeApplicationRequest(req *http.Request) (*SubmitResponse, error) {
+	// Make request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read and process response
+	return c.processApplicationResponse(resp)
+}
+
+// processApplicationResponse processes the application submission response
+func (c *Client) processApplicationResponse(resp *http.Response) (*SubmitResponse, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Print results
+	fmt.Fprintf(c.out, "🎯 Application submission HTTP Status: %d %s\n", resp.StatusCode, resp.Status)
+	fmt.Fprintf(c.out, "📄 Application submission response body: %s\n", string(body))
+
+	result := &SubmitResponse{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+	}
+
+	if result.Succeeded() {
+		fmt.Fprintln(c.out, "✅ Application submitted successfully!")
+	} else if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		fmt.Fprintln(c.out, "⚠️  Application endpoint rejected the authorization token")
+		return result, fmt.Errorf("%w: %d %s", ErrTokenRejected, resp.StatusCode, resp.Status)
+	} else {
+		fmt.Fprintln(c.out, "⚠️  Application submission completed with non-success status")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeServerCA writes the test server's certificate as a PEM CA bundle
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(block), 0644); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+	return caFile
+}
+
+func TestNewHTTPClientFromConfigTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"tls-token"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		tlsConfig   func(t *testing.T) TLSConfig
+		expectError bool
+	}{
+		{
+			name: "custom CA bundle trusts server",
+			tlsConfig: func(t *testing.T) TLSConfig {
+				return TLSConfig{CAFile: writeServerCA(t, server)}
+			},
+			expectError: false,
+		},
+		{
+			name: "insecure skip verify",
+			tlsConfig: func(t *testing.T) TLSConfig {
+				return TLSConfig{InsecureSkipVerify: true}
+			},
+			expectError: false,
+		},
+		{
+			name: "system roots reject self-signed server",
+			tlsConfig: func(t *testing.T) TLSConfig {
+				return TLSConfig{}
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.TLS = tt.tlsConfig(t)
+
+			client, err := NewHTTPClientFromConfig(config)
+			if err != nil {
+				t.Fatalf("NewHTTPClientFromConfig failed: %v", err)
+			}
+
+			token, err := getAuthTokenWithClient(client, server.URL)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected TLS error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+			if token != "tls-token" {
+				t.Errorf("Expected token 'tls-token', got %s", token)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientFromConfigErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	badCA := filepath.Join(tempDir, "bad-ca.pem")
+	if err := os.WriteFile(badCA, []byte("not a certificate"), 0644); err != nil {
+		t.Fatalf("Failed to write bad CA file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		modify        func(*Config)
+		errorContains string
+	}{
+		{
+			name:          "unsupported TLS version",
+			modify:        func(c *Config) { c.TLS.MinVersion = "2.0" },
+			errorContains: "unsupported TLS version",
+		},
+		{
+			name:          "missing CA bundle",
+			modify:        func(c *Config) { c.TLS.CAFile = filepath.Join(tempDir, "missing.pem") },
+			errorContains: "failed to read CA bundle",
+		},
+		{
+			name:          "CA bundle without certificates",
+			modify:        func(c *Config) { c.TLS.CAFile = badCA },
+			errorContains: "no valid certificates",
+		},
+		{
+			name:          "client certificate without key",
+			modify:        func(c *Config) { c.TLS.CertFile = "client.pem" },
+			errorContains: "both client certificate and key",
+		},
+		{
+			name:          "invalid proxy URL",
+			modify:        func(c *Config) { c.ProxyURL = "://bad" },
+			errorContains: "invalid proxy URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(config)
+
+			_, err := NewHTTPClientFromConfig(config)
+			if err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected error to contain '%s', got: %v", tt.errorContains, err)
+			}
+		})
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	tests := map[string]uint16{
+		"":    tls.VersionTLS12,
+		"1.0": tls.VersionTLS10,
+		"1.1": tls.VersionTLS11,
+		"1.2": tls.VersionTLS12,
+		"1.3": tls.VersionTLS13,
+	}
+
+	for input, expected := range tests {
+		version, err := parseTLSVersion(input)
+		if err != nil {
+			t.Errorf("parseTLSVersion(%q) returned error: %v", input, err)
+		}
+		if version != expected {
+			t.Errorf("parseTLSVersion(%q) = %x, expected %x", input, version, expected)
+		}
+	}
+}
+
+func TestNewHTTPClientFromConfigProxy(t *testing.T) {
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+		w.Write([]byte(`{"result":"via-proxy"}`))
+	}))
+	defer proxy.Close()
+
+	config := DefaultConfig()
+	config.ProxyURL = proxy.URL
+
+	client, err := NewHTTPClientFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewHTTPClientFromConfig failed: %v", err)
+	}
+
+	token, err := getAuthTokenWithClient(client, "http://upstream.invalid/secret")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !proxied {
+		t.Error("Expected request to go through the proxy")
+	}
+	if token != "via-proxy" {
+		t.Errorf("Expected token 'via-proxy', got %s", token)
+	}
+}
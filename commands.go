@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Command describes a micv subcommand
+type Command struct {
+	Name     string
+	Synopsis string
+	Summary  string
+	// NeedsConfig reports whether the configuration is loaded before Run
+	NeedsConfig bool
+	// Flags defines the options of the command itself, if any; they take
+	// precedence over global options of the same name
+	Flags func(fs *flag.FlagSet)
+	Run   func(configResult *ConfigResult) error
+}
+
+// defaultCommand runs when no command name is given, so that the positional
+// <name> <email> <job_title> [final_attempt] form keeps working
+const defaultCommand = "submit"
+
+// commands returns the available subcommands in help order
+func commands() []Command {
+	return []Command{
+		{Name: "submit", Synopsis: "submit [--data <file>] [<name> <email> <job_title> [final_attempt]]", Summary: "Submit an application (default command)", NeedsConfig: true, Run: runSubmitCommand},
+		{Name: "validate", Synopsis: "validate [--config-file <file|glob>]... [<data file|glob>...]", Summary: "Validate configuration and data files without submitting", NeedsConfig: true, Flags: func(fs *flag.FlagSet) { defineValidateFlags(fs) }, Run: runValidateCommand},
+		{Name: "batch", Synopsis: "batch --input <applicants.csv|dir> [--concurrency n] [--state file] [--report file]", Summary: "Submit many applicants from a CSV file or directory", NeedsConfig: true, Flags: func(fs *flag.FlagSet) { defineBatchFlags(fs, &BatchOptions{}) }, Run: runBatchSubcommand},
+		{Name: "diff", Synopsis: "diff --data <file> [--against <file>]", Summary: "Compare a payload with the last successful submission", NeedsConfig: true, Flags: func(fs *flag.FlagSet) { defineDiffFlags(fs) }, Run: func(c *ConfigResult) error { return runDiffCommand(c.Config, c.Args, c.Vars) }},
+		{Name: "generate", Synopsis: "generate data|config...", Summary: "Generate sample data.json and/or config.json files", Run: runGenerateCommand},
+		{Name: "doctor", Synopsis: "doctor", Summary: "Check connectivity to the configured endpoints", NeedsConfig: true, Run: runDoctorCommand},
+		{Name: "config", Synopsis: "config show [--explain]", Summary: "Show the effective configuration", NeedsConfig: true, Flags: func(fs *flag.FlagSet) { defineConfigFlags(fs) }, Run: func(c *ConfigResult) error { return runConfigCommand(c, c.Args) }},
+		{Name: "token", Synopsis: "token show|clear", Summary: "Inspect or clear the token cache", NeedsConfig: true, Run: runTokenSubcommand},
+		{Name: "version", Synopsis: "version", Summary: "Show version information", Run: runVersionCommand},
+		{Name: "help", Synopsis: "help [command]", Summary: "Show help for micv or a command", Run: runHelpCommand},
+	}
+}
+
+// lookupCommand returns the named command, or the default command
+func lookupCommand(name string) Command {
+	for _, command := range commands() {
+		if command.Name == name {
+			return command
+		}
+	}
+	return lookupCommand(defaultCommand)
+}
+
+// isCommand reports whether name is a known command
+func isCommand(name string) bool {
+	for _, command := range commands() {
+		if command.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCommand splits the positional arguments into a command and its
+// arguments; anything that is not a command name is an alias for submit
+func resolveCommand(args []string) (string, []string) {
+	if len(args) > 0 && isCommand(args[0]) {
+		return args[0], args[1:]
+	}
+	return defaultCommand, args
+}
+
+// commandFlagSet returns a flag set for command that shares every global
+// option, so they may also be given after the command name
+func commandFlagSet(command string, global *flag.FlagSet) *flag.FlagSet {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(global.Output())
+	global.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	fs.Usage = func() {
+		printCommandHelp(fs.Output(), command)
+	}
+	return fs
+}
+
+// parseCommandFlags parses the global options given after the command name and
+// returns the remaining arguments, including the command's own options, for
+// the command to parse
+func parseCommandFlags(command string, global *flag.FlagSet, args []string) ([]string, error) {
+	define := lookupCommand(command).Flags
+	local := flag.NewFlagSet(command, flag.ContinueOnError)
+	if define != nil {
+		define(local)
+	}
+
+	var globalArgs, rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			// Commands with options of their own parse the terminator themselves
+			if define != nil {
+				rest = append(rest, args[i:]...)
+			} else {
+				rest = append(rest, args[i+1:]...)
+			}
+			break
+		}
+
+		name, hasValue := parseFlagName(arg)
+		var f *flag.Flag
+		target := &rest
+		if name == "" || local.Lookup(name) != nil {
+			f = local.Lookup(name)
+		} else if name == "h" || global.Lookup(name) != nil {
+			f = global.Lookup(name)
+			target = &globalArgs
+		}
+
+		*target = append(*target, arg)
+		if f != nil && !hasValue && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			*target = append(*target, args[i])
+		}
+	}
+
+	if err := commandFlagSet(command, global).Parse(globalArgs); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
+
+// parseFlagName returns the name of the flag in arg, or "" when arg is not a
+// flag, and whether arg also carries the value
+func parseFlagName(arg string) (name string, hasValue bool) {
+	if len(arg) < 2 || arg[0] != '-' {
+		return "", false
+	}
+	name = strings.TrimPrefix(arg[1:], "-")
+	if name == "" || name[0] == '-' || name[0] == '=' {
+		return "", false
+	}
+	if i := strings.Index(name, "="); i >= 0 {
+		return name[:i], true
+	}
+	return name, false
+}
+
+// isBoolFlag reports whether f is a boolean flag, which takes no separate value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// runCommand runs the resolved command
+func runCommand(configResult *ConfigResult) error {
+	return lookupCommand(configResult.Command).Run(configResult)
+}
+
+// runSubmitCommand submits a single application
+func runSubmitCommand(configResult *ConfigResult) (err error) {
+	deps, err := newCommandDependencies(configResult)
+	if err != nil {
+		return err
+	}
+	logger := deps.Logger()
+
+	if err := deps.Metrics().Start(); err != nil {
+		return err
+	}
+	defer func() { finishRun(deps, err) }()
+
+	// Create application instance
+	app := NewApplication(deps)
+
+	// Load application data
+	appData, err := loadApplicationData(configResult)
+	if err != nil {
+		logger.Error("Failed to load application data", "error", err)
+		return fmt.Errorf("failed to load application data: %w", err)
+	}
+
+	// Render templates before the data is validated
+	appData, err = RenderApplicationData(appData, configResult.Vars)
+	if err != nil {
+		logger.Error("Failed to render application data", "error", err)
+		return fmt.Errorf("failed to render application data: %w", err)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(configResult.Config.Timeout+10)*time.Second)
+	defer cancel()
+
+	// Run application
+	if err := app.Run(ctx, appData); err != nil {
+		logger.Error("Application execution failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// runBatchSubcommand submits applicants in bulk
+func runBatchSubcommand(configResult *ConfigResult) error {
+	deps, err := newCommandDependencies(configResult)
+	if err != nil {
+		return err
+	}
+
+	if err := deps.Metrics().Start(); err != nil {
+		return err
+	}
+	err = runBatchCommand(deps, configResult.Args, configResult.Vars)
+	finishRun(deps, err)
+	return err
+}
+
+// finishRun exports the metrics and trace spans of a submit or batch run
+func finishRun(deps Dependencies, err error) {
+	deps.Metrics().Finish(err)
+	flushTracer(deps.Tracer(), deps.Logger())
+}
+
+// runTokenSubcommand handles `token show|clear`
+func runTokenSubcommand(configResult *ConfigResult) error {
+	if len(configResult.Args) != 1 {
+		printCommandHelp(os.Stderr, "token")
+		return fmt.Errorf("expected exactly one token action (show or clear)")
+	}
+	return runTokenCommand(configResult.Config, configResult.Args[0])
+}
+
+// runGenerateCommand handles `generate data|config...`
+func runGenerateCommand(configResult *ConfigResult) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.Usage = func() {
+		printCommandHelp(fs.Output(), "generate")
+	}
+	if err := fs.Parse(configResult.Args); err != nil {
+		return err
+	}
+
+	var generateData, generateConfig bool
+	for _, kind := range fs.Args() {
+		switch kind {
+		case "data":
+			generateData = true
+		case "config":
+			generateConfig = true
+		default:
+			return fmt.Errorf("unknown file kind %q (expected data or config)", kind)
+		}
+	}
+	if !generateData && !generateConfig {
+		fs.Usage()
+		return fmt.Errorf("nothing to generate (expected data or config)")
+	}
+
+	return generateFiles(generateData, generateConfig)
+}
+
+// runVersionCommand prints version information
+func runVersionCommand(configResult *ConfigResult) error {
+	version, buildTime, commitHash := GetVersionInfo()
+	fmt.Printf("micv version %s\n", version)
+	fmt.Printf("Built: %s\n", buildTime)
+	fmt.Printf("Commit: %s\n", commitHash)
+	return nil
+}
+
+// runHelpCommand prints general help or help for a single command
+func runHelpCommand(configResult *ConfigResult) error {
+	if len(configResult.Args) > 0 {
+		if !isCommand(configResult.Args[0]) {
+			return fmt.Errorf("unknown command %q", configResult.Args[0])
+		}
+		printCommandHelp(os.Stdout, configResult.Args[0])
+		return nil
+	}
+	printUsage(os.Stdout)
+	return nil
+}
+
+// newCommandDependencies creates the dependencies shared by network commands,
+// including record or replay mode
+func newCommandDependencies(configResult *ConfigResult) (*AppDependencies, error) {
+	if configResult.ConfigDiscovered {
+		fmt.Printf("🔧 Using configuration file: %s\n", configResult.ConfigFile)
+	}
+	if configResult.DataDiscovered {
+		fmt.Printf("📄 Using data file: %s\n", configResult.DataFile)
+	}
+	if configResult.Profile != "" {
+		fmt.Printf("🔧 Using configuration profile: %s\n", configResult.Profile)
+	}
+
+	// Determine log level based on verbose flag
+	logLevel := LogLevelInfo
+	if configResult.Verbose {
+		logLevel = LogLevelDebug
+	}
+
+	deps, err := NewAppDependencies(configResult.Config, logLevel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize dependencies: %w", err)
+	}
+
+	// Enable record or replay mode if requested
+	httpClient, err := newCassetteClient(configResult, deps.HTTPClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize record/replay mode: %w", err)
+	}
+	return deps.WithHTTPClient(httpClient), nil
+}
+
+// printCommandHelp prints the synopsis, summary and options of a command
+func printCommandHelp(w io.Writer, name string) {
+	command := lookupCommand(name)
+	fmt.Fprintf(w, "Usage: %s [OPTIONS] %s\n\n", os.Args[0], command.Synopsis)
+	fmt.Fprintf(w, "%s.\n", command.Summary)
+	if command.Flags != nil {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		command.Flags(fs)
+		fs.SetOutput(w)
+		fmt.Fprintf(w, "\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	fmt.Fprintf(w, "\nRun '%s help' for the global options.\n", os.Args[0])
+}
+
+// printUsage prints the general help message
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s [OPTIONS] <command> [arguments]\n", os.Args[0])
+	fmt.Fprintf(w, "       %s [OPTIONS] <name> <email> <job_title> [final_attempt]\n\n", os.Args[0])
+	fmt.Fprintf(w, "Commands:\n")
+	for _, command := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", command.Name, command.Summary)
+	}
+	fmt.Fprintf(w, "\nOptions:\n")
+	fmt.Fprintf(w, "  --config string\n")
+	fmt.Fprintf(w, "        Path to configuration file, or MICV_CONFIG (default: micv.json, then $XDG_CONFIG_HOME/micv/config.json|yaml)\n")
+	fmt.Fprintf(w, "  --profile string\n")
+	fmt.Fprintf(w, "        Named profile to use from the configuration file (or MICV_PROFILE)\n")
+	fmt.Fprintf(w, "  --secret-url string\n")
+	fmt.Fprintf(w, "        URL for the secret endpoint\n")
+	fmt.Fprintf(w, "  --app-url string\n")
+	fmt.Fprintf(w, "        URL for the application endpoint\n")
+	fmt.Fprintf(w, "  --timeout int\n")
+	fmt.Fprintf(w, "        Request timeout in seconds\n")
+	fmt.Fprintf(w, "  --proxy string\n")
+	fmt.Fprintf(w, "        HTTP(S) proxy URL for outbound requests\n")
+	fmt.Fprintf(w, "  --ca-cert string\n")
+	fmt.Fprintf(w, "        Path to PEM bundle of trusted root CAs\n")
+	fmt.Fprintf(w, "  --client-cert string\n")
+	fmt.Fprintf(w, "        Path to PEM client certificate for mutual TLS\n")
+	fmt.Fprintf(w, "  --client-key string\n")
+	fmt.Fprintf(w, "        Path to PEM client key for mutual TLS\n")
+	fmt.Fprintf(w, "  --tls-min-version string\n")
+	fmt.Fprintf(w, "        Minimum TLS version (1.0, 1.1, 1.2, 1.3)\n")
+	fmt.Fprintf(w, "  --insecure-skip-verify\n")
+	fmt.Fprintf(w, "        Skip TLS certificate verification (local testing only)\n")
+	fmt.Fprintf(w, "  --allow-insecure\n")
+	fmt.Fprintf(w, "        Allow plain HTTP endpoints on non-loopback hosts\n")
+	fmt.Fprintf(w, "  --token-cache-ttl int\n")
+	fmt.Fprintf(w, "        Cache the authorization token for the given number of seconds (0 disables)\n")
+	fmt.Fprintf(w, "  --auth-scheme string\n")
+	fmt.Fprintf(w, "        Authorization scheme (raw, bearer, static, hmac)\n")
+	fmt.Fprintf(w, "  --secret-mode string\n")
+	fmt.Fprintf(w, "        Secret response format (json, text, header)\n")
+	fmt.Fprintf(w, "  --secret-path string\n")
+	fmt.Fprintf(w, "        JSON path or pointer of the token in the secret response\n")
+	fmt.Fprintf(w, "  --secret-header string\n")
+	fmt.Fprintf(w, "        Response header carrying the token (header mode)\n")
+	fmt.Fprintf(w, "  --rate-limit float\n")
+	fmt.Fprintf(w, "        Maximum requests per second to each host (0 disables)\n")
+	fmt.Fprintf(w, "  --rate-burst int\n")
+	fmt.Fprintf(w, "        Burst size for --rate-limit (default 1)\n")
+	fmt.Fprintf(w, "  --data string\n")
+	fmt.Fprintf(w, "        Path to JSON file containing application data\n")
+	fmt.Fprintf(w, "  --var key=value\n")
+	fmt.Fprintf(w, "        Template variable for data files (repeatable)\n")
+	fmt.Fprintf(w, "  --record string\n")
+	fmt.Fprintf(w, "        Record HTTP interactions into the given directory\n")
+	fmt.Fprintf(w, "  --replay string\n")
+	fmt.Fprintf(w, "        Replay HTTP interactions from the given directory without network access\n")
+	fmt.Fprintf(w, "  --metrics-file string\n")
+	fmt.Fprintf(w, "        Write Prometheus metrics to this textfile after each run\n")
+	fmt.Fprintf(w, "  --metrics-addr string\n")
+	fmt.Fprintf(w, "        Serve Prometheus metrics on this address (host:port) while running\n")
+	fmt.Fprintf(w, "  --trace-file string\n")
+	fmt.Fprintf(w, "        Append trace spans to this JSON lines file\n")
+	fmt.Fprintf(w, "  --otlp-endpoint string\n")
+	fmt.Fprintf(w, "        Export trace spans to this OTLP/HTTP collector (e.g. http://localhost:4318)\n")
+	fmt.Fprintf(w, "  --force\n")
+	fmt.Fprintf(w, "        Submit even if an identical application was already submitted successfully\n")
+	fmt.Fprintf(w, "  --flags-over-env\n")
+	fmt.Fprintf(w, "        Let command-line flags take precedence over MICV_* environment variables\n")
+	fmt.Fprintf(w, "  --verbose\n")
+	fmt.Fprintf(w, "        Enable verbose logging (debug level)\n")
+	fmt.Fprintf(w, "  --help\n")
+	fmt.Fprintf(w, "        Show help message\n")
+	fmt.Fprintf(w, "\nArguments (when --data is not used):\n")
+	fmt.Fprintf(w, "  name           Full name of the applicant\n")
+	fmt.Fprintf(w, "  email          Email address of the applicant\n")
+	fmt.Fprintf(w, "  job_title      Job title to apply for\n")
+	fmt.Fprintf(w, "  final_attempt  Set to 'true' for final attempt (optional)\n")
+	fmt.Fprintf(w, "\nExamples:\n")
+	fmt.Fprintf(w, "  %s \"John Doe\" \"john@example.com\" \"Software Engineer\"\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --config config.json submit \"John Doe\" \"john@example.com\" \"Software Engineer\" true\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --secret-url https://custom.com/secret \"John Doe\" \"john@example.com\" \"Software Engineer\"\n", os.Args[0])
+	fmt.Fprintf(w, "  %s submit --data application.json\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --config config.json --profile staging-mock submit --data application.json\n", os.Args[0])
+	fmt.Fprintf(w, "  %s validate --config-file config.json 'applicants/*.json'\n", os.Args[0])
+	fmt.Fprintf(w, "  %s generate data config\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --record ./cassettes/run1 submit --data application.json\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --replay ./cassettes/run1 submit --data application.json\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --token-cache-ttl 3600 submit --data application.json\n", os.Args[0])
+	fmt.Fprintf(w, "  %s token show\n", os.Args[0])
+	fmt.Fprintf(w, "  %s doctor\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --config config.json config show --explain\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --verbose \"John Doe\" \"john@example.com\" \"Software Engineer\"\n", os.Args[0])
+}
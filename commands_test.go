@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveCommand(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedCommand string
+		expectedArgs    []string
+	}{
+		{"no arguments", []string{}, "submit", []string{}},
+		{"positional alias", []string{"John Doe", "john@example.com", "Software Engineer"}, "submit", []string{"John Doe", "john@example.com", "Software Engineer"}},
+		{"explicit submit", []string{"submit", "John Doe"}, "submit", []string{"John Doe"}},
+		{"token", []string{"token", "show"}, "token", []string{"show"}},
+		{"generate", []string{"generate", "data", "config"}, "generate", []string{"data", "config"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, args := resolveCommand(tt.args)
+			if command != tt.expectedCommand {
+				t.Errorf("Expected command %s, got %s", tt.expectedCommand, command)
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromArgsCommands(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectedCommand string
+		expectedArgs    []string
+	}{
+		{"version command", []string{"version"}, "version", nil},
+		{"deprecated version flag", []string{"--version"}, "version", nil},
+		{"deprecated help flag", []string{"--help"}, "help", nil},
+		{"deprecated generate flags", []string{"--generate-data-json", "--generate-config-json"}, "generate", []string{"data", "config"}},
+		{"help for a command", []string{"submit", "--help"}, "help", []string{"submit"}},
+		{"options after submit", []string{"submit", "--timeout", "45", "John Doe", "john@example.com", "Engineer"}, "submit", []string{"John Doe", "john@example.com", "Engineer"}},
+		{"batch keeps its own flags", []string{"batch", "--input", "applicants.csv"}, "batch", []string{"--input", "applicants.csv"}},
+		{"options after batch", []string{"batch", "--input", "applicants.csv", "--timeout", "45", "--concurrency=4"}, "batch", []string{"--input", "applicants.csv", "--concurrency=4"}},
+		{"diff keeps its own data flag", []string{"diff", "--data", "new.json", "--verbose"}, "diff", []string{"--data", "new.json"}},
+		{"options after config show", []string{"config", "show", "--explain", "--timeout", "45"}, "config", []string{"show", "--explain"}},
+		{"terminator after submit", []string{"submit", "--", "--John", "john@example.com", "Engineer"}, "submit", []string{"--John", "john@example.com", "Engineer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configResult, err := LoadConfigFromArgs(tt.args)
+			if err != nil {
+				t.Fatalf("LoadConfigFromArgs failed: %v", err)
+			}
+			if configResult.Command != tt.expectedCommand {
+				t.Errorf("Expected command %s, got %s", tt.expectedCommand, configResult.Command)
+			}
+			if len(tt.expectedArgs) > 0 && !reflect.DeepEqual(configResult.Args, tt.expectedArgs) {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, configResult.Args)
+			}
+		})
+	}
+
+	configResult, err := LoadConfigFromArgs([]string{"submit", "--timeout", "45", "John Doe"})
+	if err != nil {
+		t.Fatalf("LoadConfigFromArgs failed: %v", err)
+	}
+	if configResult.Config.Timeout != 45 {
+		t.Errorf("Expected options after the command to apply, got timeout %d", configResult.Config.Timeout)
+	}
+}
+
+func TestGlobalOptionsAfterCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	if err := os.WriteFile("custom.json", []byte(`{"timeout_seconds": 77}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range []string{"validate", "doctor", "batch", "diff", "token", "config"} {
+		configResult, err := LoadConfigFromArgs([]string{command, "--config", "custom.json", "--verbose"})
+		if err != nil {
+			t.Fatalf("%s: LoadConfigFromArgs failed: %v", command, err)
+		}
+		if configResult.Config.Timeout != 77 || !configResult.Verbose {
+			t.Errorf("%s: expected options after the command to apply, got timeout %d, verbose %v",
+				command, configResult.Config.Timeout, configResult.Verbose)
+		}
+		if len(configResult.Args) != 0 {
+			t.Errorf("%s: expected the global options to be consumed, got args %v", command, configResult.Args)
+		}
+	}
+}
+
+func TestPrintCommandHelpShowsOptions(t *testing.T) {
+	var buf bytes.Buffer
+	printCommandHelp(&buf, "batch")
+	for _, option := range []string{"-input", "-concurrency", "-state", "-report"} {
+		if !strings.Contains(buf.String(), option) {
+			t.Errorf("Expected batch help to list %s, got:\n%s", option, buf.String())
+		}
+	}
+
+	buf.Reset()
+	printCommandHelp(&buf, "doctor")
+	if strings.Contains(buf.String(), "Options:") {
+		t.Errorf("Expected no options section for doctor, got:\n%s", buf.String())
+	}
+}
+
+func TestGenerateCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	if err := runGenerateCommand(&ConfigResult{Args: []string{"data", "config"}}); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	for _, file := range []string{"data.json", "config.json"} {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("Expected %s to be generated: %v", file, err)
+		}
+	}
+
+	if err := runGenerateCommand(&ConfigResult{Args: []string{"cv"}}); err == nil {
+		t.Error("Expected error for unknown file kind")
+	}
+	if err := runGenerateCommand(&ConfigResult{}); err == nil {
+		t.Error("Expected error when nothing is requested")
+	}
+}
+
+func TestValidateCommand(t *testing.T) {
+	applicant := createDefaultApplicationData("John Doe", "john@example.com", "Software Engineer", nil)
+	configResult := &ConfigResult{
+		Config:    DefaultConfig(),
+		Applicant: &applicant,
+	}
+	if err := runValidateCommand(configResult); err != nil {
+		t.Errorf("Expected valid application, got %v", err)
+	}
+
+	applicant.Email = "not-an-email"
+	err := runValidateCommand(configResult)
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeValidation {
+		t.Errorf("Expected %s error, got %v", ErrCodeValidation, err)
+	}
+
+	configResult.Config.ApplicationURL = "htps://au.mitimes.com/careers/apply"
+	err = runValidateCommand(configResult)
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeConfig {
+		t.Errorf("Expected %s error, got %v", ErrCodeConfig, err)
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"runtime/debug"
-	"strconv"
 	"strings"
+	"time"
+
+	"micv/validate"
 )
 
 // Build-time variables (set via -ldflags)
@@ -37,16 +42,85 @@ func GetVersionInfo() (version, buildTime, commitHash string) {
 
 // Config holds all configuration options
 type Config struct {
-	SecretURL      string `json:"secret_url"`
-	ApplicationURL string `json:"application_url"`
-	Timeout        int    `json:"timeout_seconds"`
+	SecretURL           string               `json:"secret_url"`
+	ApplicationURL      string               `json:"application_url"`
+	Timeout             int                  `json:"timeout_seconds"`
+	ProxyURL            string               `json:"proxy_url,omitempty"`
+	TLS                 TLSConfig            `json:"tls"`
+	Retry               RetrySettings        `json:"retry"`
+	TokenCacheTTL       int                  `json:"token_cache_ttl_seconds,omitempty"`
+	TokenCacheFile      string               `json:"token_cache_file,omitempty"`
+	Auth                AuthConfig           `json:"auth"`
+	SecretResponse      SecretResponseConfig `json:"secret_response"`
+	RateLimits          map[string]RateLimit `json:"rate_limits,omitempty"`
+	SubmissionStoreFile string               `json:"submission_store_file,omitempty"`
+	AllowInsecure       bool                 `json:"allow_insecure,omitempty"`
+	Hooks               HookConfig           `json:"hooks,omitzero"`
+	Notifications       NotificationConfig   `json:"notifications,omitzero"`
+	Metrics             MetricsConfig        `json:"metrics,omitzero"`
+	Tracing             TracingConfig        `json:"tracing,omitzero"`
+	Attachments         AttachmentConfig     `json:"attachments,omitzero"`
+	Force               bool                 `json:"-"`
+}
+
+// TLSConfig holds TLS options for outbound HTTPS connections
+type TLSConfig struct {
+	CAFile             string `json:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	MinVersion         string `json:"min_version,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// RetrySettings overrides the default retry behaviour of outbound requests
+type RetrySettings struct {
+	MaxAttempts    int `json:"max_attempts,omitempty"`
+	InitialDelayMS int `json:"initial_delay_ms,omitempty"`
+	MaxDelayMS     int `json:"max_delay_ms,omitempty"`
+}
+
+// RetryConfig returns the retry configuration with any configured overrides applied
+func (c *Config) RetryConfig() RetryConfig {
+	retry := DefaultRetryConfig()
+	if c.Retry.MaxAttempts > 0 {
+		retry.MaxAttempts = c.Retry.MaxAttempts
+	}
+	if c.Retry.InitialDelayMS > 0 {
+		retry.InitialDelay = time.Duration(c.Retry.InitialDelayMS) * time.Millisecond
+	}
+	if c.Retry.MaxDelayMS > 0 {
+		retry.MaxDelay = time.Duration(c.Retry.MaxDelayMS) * time.Millisecond
+	}
+	return retry
 }
 
 // ConfigResult holds the config and additional flags
 type ConfigResult struct {
-	Config   *Config
-	DataFile string
-	Verbose  bool
+	// Command is the resolved subcommand and Args its remaining arguments
+	Command string
+	Args    []string
+
+	Config    *Config
+	DataFile  string
+	Verbose   bool
+	RecordDir string
+	ReplayDir string
+	Profile   string
+	Sources   *ConfigSources
+
+	// ConfigFile is the configuration file in use and ConfigDiscovered
+	// reports whether it was found without --config; DataDiscovered
+	// likewise reports whether DataFile was found without --data or MICV_DATA
+	ConfigFile       string
+	ConfigDiscovered bool
+	DataDiscovered   bool
+
+	// Applicant holds application data from MICV_NAME, MICV_EMAIL and
+	// MICV_JOB_TITLE when no data file or arguments are given
+	Applicant *ApplicationData
+
+	// Vars holds the --var values used to render data file templates
+	Vars map[string]string
 }
 
 // DefaultConfig returns the default configuration
@@ -58,128 +132,329 @@ func DefaultConfig() *Config {
 	}
 }
 
-// LoadConfig loads configuration from file and command line arguments
+// LoadConfig loads configuration from file and the process command line
 func LoadConfig() (*ConfigResult, error) {
+	return LoadConfigFromArgs(os.Args[1:])
+}
+
+// LoadConfigFromArgs parses the global options and command from args and
+// loads the configuration from defaults, file, flags and environment
+func LoadConfigFromArgs(args []string) (*ConfigResult, error) {
+	return loadConfigFromArgs(flag.NewFlagSet("micv", flag.ContinueOnError), args)
+}
+
+// loadConfigFromArgs implements LoadConfigFromArgs, defining the global
+// options on fs
+func loadConfigFromArgs(fs *flag.FlagSet, args []string) (*ConfigResult, error) {
 	config := DefaultConfig()
 
 	// Define command line flags
 	var (
-		configFile         = flag.String("config", "", "Path to configuration file")
-		secretURL          = flag.String("secret-url", "", "URL for the secret endpoint")
-		appURL             = flag.String("app-url", "", "URL for the application endpoint")
-		timeout            = flag.Int("timeout", 0, "Request timeout in seconds")
-		dataFile           = flag.String("data", "", "Path to JSON file containing application data")
-		generateDataJSON   = flag.Bool("generate-data-json", false, "Generate sample data.json file")
-		generateConfigJSON = flag.Bool("generate-config-json", false, "Generate sample config.json file")
-		verbose            = flag.Bool("verbose", false, "Enable verbose logging (debug level)")
-		showHelp           = flag.Bool("help", false, "Show help message")
-		showVersion        = flag.Bool("version", false, "Show version information")
+		configFile         = fs.String("config", "", "Path to configuration file")
+		profile            = fs.String("profile", "", "Named profile to use from the configuration file")
+		secretURL          = fs.String("secret-url", "", "URL for the secret endpoint")
+		appURL             = fs.String("app-url", "", "URL for the application endpoint")
+		timeout            = fs.Int("timeout", 0, "Request timeout in seconds")
+		proxyURL           = fs.String("proxy", "", "HTTP(S) proxy URL for outbound requests")
+		caFile             = fs.String("ca-cert", "", "Path to PEM bundle of trusted root CAs")
+		certFile           = fs.String("client-cert", "", "Path to PEM client certificate for mutual TLS")
+		keyFile            = fs.String("client-key", "", "Path to PEM client key for mutual TLS")
+		tlsMinVersion      = fs.String("tls-min-version", "", "Minimum TLS version (1.0, 1.1, 1.2, 1.3)")
+		insecureSkipVerify = fs.Bool("insecure-skip-verify", false, "Skip TLS certificate verification (local testing only)")
+		allowInsecure      = fs.Bool("allow-insecure", false, "Allow plain HTTP endpoints on non-loopback hosts")
+		tokenCacheTTL      = fs.Int("token-cache-ttl", 0, "Cache the authorization token for the given number of seconds (0 disables)")
+		authScheme         = fs.String("auth-scheme", "", "Authorization scheme (raw, bearer, static, hmac)")
+		secretMode         = fs.String("secret-mode", "", "Secret response format (json, text, header)")
+		secretPath         = fs.String("secret-path", "", "JSON path or pointer of the token in the secret response")
+		secretHeader       = fs.String("secret-header", "", "Response header carrying the token (header mode)")
+		rateLimit          = fs.Float64("rate-limit", 0, "Maximum requests per second to each host (0 disables)")
+		rateBurst          = fs.Int("rate-burst", 1, "Burst size for --rate-limit")
+		dataFile           = fs.String("data", "", "Path to JSON file containing application data")
+		generateDataJSON   = fs.Bool("generate-data-json", false, "Deprecated: use the generate data command")
+		generateConfigJSON = fs.Bool("generate-config-json", false, "Deprecated: use the generate config command")
+		recordDir          = fs.String("record", "", "Record HTTP interactions into the given directory")
+		replayDir          = fs.String("replay", "", "Replay HTTP interactions from the given directory without network access")
+		metricsFile        = fs.String("metrics-file", "", "Write Prometheus metrics to this textfile after each run")
+		metricsAddr        = fs.String("metrics-addr", "", "Serve Prometheus metrics on this address (host:port) while running")
+		traceFile          = fs.String("trace-file", "", "Append trace spans to this JSON lines file")
+		otlpEndpoint       = fs.String("otlp-endpoint", "", "Export trace spans to this OTLP/HTTP collector (e.g. http://localhost:4318)")
+		force              = fs.Bool("force", false, "Submit even if an identical application was already submitted successfully")
+		flagsOverEnv       = fs.Bool("flags-over-env", false, "Let command-line flags take precedence over MICV_* environment variables")
+		verbose            = fs.Bool("verbose", false, "Enable verbose logging (debug level)")
+		showHelp           = fs.Bool("help", false, "Show help message")
+		showVersion        = fs.Bool("version", false, "Deprecated: use the version command")
 	)
+	var templateVars stringListFlag
+	fs.Var(&templateVars, "var", "Template variable for data files as key=value (repeatable)")
+
+	fs.Usage = func() {
+		printUsage(fs.Output())
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
 
-	// Custom usage function
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] [<name> <email> <job_title> [final_attempt]]\n\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "Options:\n")
-		fmt.Fprintf(os.Stderr, "  --config string\n")
-		fmt.Fprintf(os.Stderr, "        Path to configuration file\n")
-		fmt.Fprintf(os.Stderr, "  --secret-url string\n")
-		fmt.Fprintf(os.Stderr, "        URL for the secret endpoint\n")
-		fmt.Fprintf(os.Stderr, "  --app-url string\n")
-		fmt.Fprintf(os.Stderr, "        URL for the application endpoint\n")
-		fmt.Fprintf(os.Stderr, "  --timeout int\n")
-		fmt.Fprintf(os.Stderr, "        Request timeout in seconds\n")
-		fmt.Fprintf(os.Stderr, "  --data string\n")
-		fmt.Fprintf(os.Stderr, "        Path to JSON file containing application data\n")
-		fmt.Fprintf(os.Stderr, "  --generate-data-json\n")
-		fmt.Fprintf(os.Stderr, "        Generate sample data.json file\n")
-		fmt.Fprintf(os.Stderr, "  --generate-config-json\n")
-		fmt.Fprintf(os.Stderr, "        Generate sample config.json file\n")
-		fmt.Fprintf(os.Stderr, "  --verbose\n")
-		fmt.Fprintf(os.Stderr, "        Enable verbose logging (debug level)\n")
-		fmt.Fprintf(os.Stderr, "  --version\n")
-		fmt.Fprintf(os.Stderr, "        Show version information\n")
-		fmt.Fprintf(os.Stderr, "  --help\n")
-		fmt.Fprintf(os.Stderr, "        Show help message\n")
-		fmt.Fprintf(os.Stderr, "\nArguments (when --data is not used):\n")
-		fmt.Fprintf(os.Stderr, "  name           Full name of the applicant\n")
-		fmt.Fprintf(os.Stderr, "  email          Email address of the applicant\n")
-		fmt.Fprintf(os.Stderr, "  job_title      Job title to apply for\n")
-		fmt.Fprintf(os.Stderr, "  final_attempt  Set to 'true' for final attempt (optional)\n")
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
-		fmt.Fprintf(os.Stderr, "  %s \"John Doe\" \"john@example.com\" \"Software Engineer\"\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s --config config.json \"John Doe\" \"john@example.com\" \"Software Engineer\" true\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s --secret-url https://custom.com/secret \"John Doe\" \"john@example.com\" \"Software Engineer\"\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s --data application.json\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s --config config.json --data application.json\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s --generate-data-json\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s --generate-config-json\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s --generate-data-json --generate-config-json\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s --verbose \"John Doe\" \"john@example.com\" \"Software Engineer\"\n", os.Args[0])
-	}
-
-	flag.Parse()
-
-	// Handle generation flags
-	if *generateDataJSON || *generateConfigJSON {
-		return handleGenerateFiles(*generateDataJSON, *generateConfigJSON)
-	}
-
-	if *showVersion {
-		version, buildTime, commitHash := GetVersionInfo()
-		fmt.Fprintf(os.Stderr, "micv version %s\n", version)
-		fmt.Fprintf(os.Stderr, "Built: %s\n", buildTime)
-		fmt.Fprintf(os.Stderr, "Commit: %s\n", commitHash)
-		os.Exit(0)
+	// Resolve the command; the deprecated mode flags map onto their commands
+	command, commandArgs := resolveCommand(fs.Args())
+	switch {
+	case *showHelp:
+		command, commandArgs = "help", nil
+	case *showVersion:
+		command, commandArgs = "version", nil
+	case *generateDataJSON || *generateConfigJSON:
+		command, commandArgs = "generate", nil
+		if *generateDataJSON {
+			commandArgs = append(commandArgs, "data")
+		}
+		if *generateConfigJSON {
+			commandArgs = append(commandArgs, "config")
+		}
 	}
 
+	// Commands that need no configuration run with the defaults
+	if !lookupCommand(command).NeedsConfig {
+		return &ConfigResult{Config: config, Command: command, Args: commandArgs}, nil
+	}
+
+	// The global options may also be given after the command name
+	acceptsApplicant := command == "submit" || command == "validate"
+	commandArgs, err := parseCommandFlags(command, fs, commandArgs)
+	if err != nil {
+		return nil, err
+	}
 	if *showHelp {
-		flag.Usage()
-		os.Exit(0)
+		return &ConfigResult{Config: config, Command: "help", Args: []string{command}}, nil
+	}
+
+	vars, err := parseTemplateVars(templateVars)
+	if err != nil {
+		return nil, err
 	}
 
+	// Run-level settings from the environment apply when the flag is not given
+	runEnv, err := loadRunEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	if *dataFile == "" && acceptsApplicant && len(commandArgs) == 0 {
+		*dataFile = runEnv.DataFile
+	}
+	if *recordDir == "" {
+		*recordDir = runEnv.RecordDir
+	}
+	if *replayDir == "" {
+		*replayDir = runEnv.ReplayDir
+	}
+	if *configFile == "" {
+		*configFile = runEnv.ConfigFile
+	}
+
+	// Applicant variables stand in for the positional arguments
+	var applicant *ApplicationData
+	if runEnv.Applicant != nil && acceptsApplicant && len(commandArgs) == 0 {
+		if *dataFile != "" {
+			return nil, NewAppError(ErrCodeConfig, "Cannot combine a data file with MICV_NAME, MICV_EMAIL and MICV_JOB_TITLE", nil).
+				WithContext("data_file", *dataFile)
+		}
+		applicant = runEnv.Applicant
+	}
+
+	// Fall back to the first configuration file found in the standard locations
+	configDiscovered := false
+	if *configFile == "" {
+		if discovered := discoverConfigFile(); discovered != "" {
+			*configFile = discovered
+			configDiscovered = true
+		}
+	}
+
+	// Fall back to a default data file when no applicant is given on the command line
+	dataDiscovered := false
+	if *dataFile == "" && acceptsApplicant && len(commandArgs) == 0 && applicant == nil {
+		*dataFile = discoverDataFile()
+		dataDiscovered = *dataFile != ""
+	}
+
+	// Select the profile, preferring the flag over MICV_PROFILE
+	selectedProfile := *profile
+	if selectedProfile == "" {
+		selectedProfile = profileFromEnvironment()
+	}
+	if selectedProfile != "" && *configFile == "" {
+		return nil, fmt.Errorf("profile %q requires a configuration file (--config)", selectedProfile)
+	}
+
+	sources := NewConfigSources(config)
+
 	// Load from config file if specified
 	if *configFile != "" {
-		if err := loadConfigFromFile(*configFile, config); err != nil {
+		if err := loadConfigProfileFromFile(*configFile, selectedProfile, config); err != nil {
 			return nil, fmt.Errorf("failed to load config file: %w", err)
 		}
+		fields, err := configFileFields(*configFile, selectedProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file: %w", err)
+		}
+		sources.Track(config, fileSource(*configFile, selectedProfile), fields)
 	}
 
 	// Override with command line arguments if provided
-	if *secretURL != "" {
-		config.SecretURL = *secretURL
-	}
-	if *appURL != "" {
-		config.ApplicationURL = *appURL
+	applyFlags := func() {
+		if *secretURL != "" {
+			config.SecretURL = *secretURL
+		}
+		if *appURL != "" {
+			config.ApplicationURL = *appURL
+		}
+		if *timeout > 0 {
+			config.Timeout = *timeout
+		}
+		if *proxyURL != "" {
+			config.ProxyURL = *proxyURL
+		}
+		if *caFile != "" {
+			config.TLS.CAFile = *caFile
+		}
+		if *certFile != "" {
+			config.TLS.CertFile = *certFile
+		}
+		if *keyFile != "" {
+			config.TLS.KeyFile = *keyFile
+		}
+		if *tlsMinVersion != "" {
+			config.TLS.MinVersion = *tlsMinVersion
+		}
+		if *insecureSkipVerify {
+			config.TLS.InsecureSkipVerify = true
+		}
+		if *allowInsecure {
+			config.AllowInsecure = true
+		}
+		if *tokenCacheTTL > 0 {
+			config.TokenCacheTTL = *tokenCacheTTL
+		}
+		if *authScheme != "" {
+			config.Auth.Scheme = *authScheme
+		}
+		if *secretMode != "" {
+			config.SecretResponse.Mode = *secretMode
+		}
+		if *secretPath != "" {
+			config.SecretResponse.Path = *secretPath
+		}
+		if *secretHeader != "" {
+			config.SecretResponse.Header = *secretHeader
+		}
+		if *metricsFile != "" {
+			config.Metrics.File = *metricsFile
+		}
+		if *metricsAddr != "" {
+			config.Metrics.Addr = *metricsAddr
+		}
+		if *traceFile != "" {
+			config.Tracing.File = *traceFile
+		}
+		if *otlpEndpoint != "" {
+			config.Tracing.OTLPEndpoint = *otlpEndpoint
+		}
+		if *force {
+			config.Force = true
+		}
+		if *rateLimit > 0 {
+			if config.RateLimits == nil {
+				config.RateLimits = make(map[string]RateLimit)
+			}
+			config.RateLimits[defaultRateLimitHost] = RateLimit{RequestsPerSecond: *rateLimit, Burst: *rateBurst}
+		}
 	}
-	if *timeout > 0 {
-		config.Timeout = *timeout
+
+	// Environment variables override flags unless --flags-over-env is set
+	if *flagsOverEnv {
+		if err := loadFromEnvironment(config); err != nil {
+			return nil, err
+		}
+		sources.TrackEnvironment(config)
+		applyFlags()
+		sources.TrackFlags(config, fs)
+	} else {
+		applyFlags()
+		sources.TrackFlags(config, fs)
+		if err := loadFromEnvironment(config); err != nil {
+			return nil, err
+		}
+		sources.TrackEnvironment(config)
 	}
 
-	loadFromEnvironment(config)
+	config.SecretURL = normalizeEndpointURL(config.SecretURL)
+	config.ApplicationURL = normalizeEndpointURL(config.ApplicationURL)
 
 	return &ConfigResult{
-		Config:   config,
-		DataFile: *dataFile,
-		Verbose:  *verbose,
+		Command:   command,
+		Args:      commandArgs,
+		Config:    config,
+		DataFile:  *dataFile,
+		Verbose:   *verbose || runEnv.Verbose,
+		RecordDir: *recordDir,
+		ReplayDir: *replayDir,
+		Profile:   selectedProfile,
+		Sources:   sources,
+		Vars:      vars,
+
+		ConfigFile:       *configFile,
+		ConfigDiscovered: configDiscovered,
+		DataDiscovered:   dataDiscovered,
+		Applicant:        applicant,
 	}, nil
 }
 
 // loadConfigFromFile loads configuration from a JSON file
 func loadConfigFromFile(filename string, config *Config) error {
-	file, err := os.Open(filename)
+	return loadConfigProfileFromFile(filename, "", config)
+}
+
+// loadConfigProfileFromFile loads the base configuration from a JSON or YAML file and
+// overlays the named profile when one is given
+func loadConfigProfileFromFile(filename, profile string, config *Config) error {
+	data, err := readConfigDocument(filename)
 	if err != nil {
-		return fmt.Errorf("failed to open config file: %w", err)
+		return err
 	}
-	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(config); err != nil {
+	if err := json.Unmarshal(data, config); err != nil {
 		return fmt.Errorf("failed to decode config file: %w", err)
 	}
 
+	if profile != "" {
+		if err := applyConfigProfile(data, profile, config); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// readConfigDocument reads a JSON or YAML config file as JSON with variables
+// and file references expanded
+func readConfigDocument(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+
+	if isYAMLFile(filename) {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to decode config file: %w", err)
+		}
+	}
+
+	if data, err = expandReferences(data, filepath.Dir(filename)); err != nil {
+		return nil, fmt.Errorf("failed to decode config file: %w", err)
+	}
+
+	return data, nil
+}
+
 // SaveConfig saves the current configuration to a file
 func SaveConfig(config *Config, filename string) error {
 	file, err := os.Create(filename)
@@ -199,170 +474,171 @@ func SaveConfig(config *Config, filename string) error {
 
 // LoadApplicationData loads application data from a JSON file
 func LoadApplicationData(filename string) (*ApplicationData, error) {
-	file, err := os.Open(filename)
+	appData, err := readApplicationDataFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open data file: %w", err)
-	}
-	defer file.Close()
-
-	var appData ApplicationData
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&appData); err != nil {
-		return nil, fmt.Errorf("failed to decode data file: %w", err)
+		return nil, err
 	}
 
 	// Validate required fields
-	if err := validateApplicationData(&appData); err != nil {
+	if err := validateApplicationData(appData); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	return &appData, nil
+	return appData, nil
 }
 
-// ValidationRule represents a validation function
-type ValidationRule[T any] func(T) error
-
-// Validator provides functional validation capabilities
-type Validator[T any] struct {
-	rules []ValidationRule[T]
+// validateApplicationData validates that required fields are present and not empty
+func validateApplicationData(appData *ApplicationData) error {
+	return validate.RequiredFields(appData)
 }
 
-// NewValidator creates a new validator
-func NewValidator[T any]() *Validator[T] {
-	return &Validator[T]{rules: make([]ValidationRule[T], 0)}
+// validateApplicationDataFunctional provides functional validation
+func validateApplicationDataFunctional(data ApplicationData) Result[ApplicationData] {
+	return validate.ApplicationData(data)
 }
 
-// AddRule adds a validation rule
-func (v *Validator[T]) AddRule(rule ValidationRule[T]) *Validator[T] {
-	v.rules = append(v.rules, rule)
-	return v
-}
+// ValidateConfig validates the configuration
+func ValidateConfig(config *Config) error {
+	if config.SecretURL == "" {
+		return fmt.Errorf("secret URL is required")
+	}
 
-// Validate runs all validation rules
-func (v *Validator[T]) Validate(value T) Result[T] {
-	for _, rule := range v.rules {
-		if err := rule(value); err != nil {
-			return NewError[T](err)
-		}
+	if config.ApplicationURL == "" {
+		return fmt.Errorf("application URL is required")
 	}
-	return NewResult(value)
-}
 
-// Common validation rules for application data
-func RequiredField(fieldName string) ValidationRule[string] {
-	return func(value string) error {
-		if strings.TrimSpace(value) == "" {
-			return fmt.Errorf("%s is required", fieldName)
-		}
-		return nil
+	if _, err := validateEndpointURL(config.SecretURL, config.AllowInsecure); err != nil {
+		return fmt.Errorf("invalid secret URL: %w", err)
 	}
-}
 
-func EmailFormat() ValidationRule[string] {
-	return func(value string) error {
-		if !strings.Contains(value, "@") || !strings.Contains(value, ".") {
-			return fmt.Errorf("invalid email format")
-		}
-		return nil
+	if _, err := validateEndpointURL(config.ApplicationURL, config.AllowInsecure); err != nil {
+		return fmt.Errorf("invalid application URL: %w", err)
 	}
-}
 
-func MinLength(min int) ValidationRule[string] {
-	return func(value string) error {
-		if len(strings.TrimSpace(value)) < min {
-			return fmt.Errorf("must be at least %d characters", min)
-		}
-		return nil
+	if config.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
 	}
-}
 
-// validateApplicationData validates that required fields are present and not empty
-func validateApplicationData(appData *ApplicationData) error {
-	var missingFields []string
+	if _, err := parseTLSVersion(config.TLS.MinVersion); err != nil {
+		return err
+	}
 
-	if strings.TrimSpace(appData.Name) == "" {
-		missingFields = append(missingFields, "name")
+	if config.Hooks.TimeoutSeconds < 0 {
+		return fmt.Errorf("hook timeout cannot be negative")
 	}
-	if strings.TrimSpace(appData.Email) == "" {
-		missingFields = append(missingFields, "email")
+
+	if config.Notifications.TimeoutSeconds < 0 {
+		return fmt.Errorf("notification timeout cannot be negative")
 	}
-	if strings.TrimSpace(appData.JobTitle) == "" {
-		missingFields = append(missingFields, "job_title")
+	for name, webhookURL := range config.Notifications.Webhooks {
+		if _, err := validateServiceURL(webhookURL); err != nil {
+			return fmt.Errorf("invalid webhook URL for %s: %w", name, err)
+		}
 	}
 
-	if len(missingFields) > 0 {
-		return fmt.Errorf("missing required fields: %s", strings.Join(missingFields, ", "))
+	if config.Metrics.Addr != "" {
+		if _, _, err := net.SplitHostPort(config.Metrics.Addr); err != nil {
+			return fmt.Errorf("invalid metrics address: %w", err)
+		}
 	}
 
-	return nil
-}
-
-// validateApplicationDataFunctional provides functional validation
-func validateApplicationDataFunctional(data ApplicationData) Result[ApplicationData] {
-	nameValidator := NewValidator[string]().
-		AddRule(RequiredField("name")).
-		AddRule(MinLength(2))
+	if config.Tracing.OTLPEndpoint != "" {
+		if _, err := validateServiceURL(config.Tracing.OTLPEndpoint); err != nil {
+			return fmt.Errorf("invalid OTLP endpoint: %w", err)
+		}
+	}
 
-	emailValidator := NewValidator[string]().
-		AddRule(RequiredField("email")).
-		AddRule(EmailFormat())
+	if config.Attachments.MaxFileSizeKB < 0 || config.Attachments.MaxTotalSizeKB < 0 {
+		return fmt.Errorf("attachment size limits must not be negative")
+	}
 
-	jobTitleValidator := NewValidator[string]().
-		AddRule(RequiredField("job_title")).
-		AddRule(MinLength(3))
+	if config.Retry.MaxAttempts < 0 || config.Retry.InitialDelayMS < 0 || config.Retry.MaxDelayMS < 0 {
+		return fmt.Errorf("retry settings must not be negative")
+	}
 
-	// Validate all fields
-	if result := nameValidator.Validate(data.Name); result.IsError() {
-		return NewError[ApplicationData](result.Error)
+	for host, limit := range config.RateLimits {
+		if limit.RequestsPerSecond < 0 || limit.Burst < 0 {
+			return fmt.Errorf("rate limit for %s must not be negative", host)
+		}
 	}
 
-	if result := emailValidator.Validate(data.Email); result.IsError() {
-		return NewError[ApplicationData](result.Error)
+	return nil
+}
+
+// validateEndpointURL checks that raw is an absolute http(s) URL with a host.
+// Plain HTTP is refused for hosts other than loopback unless allowInsecure is
+// set, since the authorization token is sent in a request header.
+func validateEndpointURL(raw string, allowInsecure bool) (*url.URL, error) {
+	u, err := validateServiceURL(raw)
+	if err != nil {
+		return nil, err
 	}
 
-	if result := jobTitleValidator.Validate(data.JobTitle); result.IsError() {
-		return NewError[ApplicationData](result.Error)
+	if strings.EqualFold(u.Scheme, "http") && !allowInsecure && !isLoopbackHost(u.Hostname()) {
+		return nil, fmt.Errorf("plain HTTP to %s would send the token unencrypted (use https or --allow-insecure)", u.Host)
 	}
 
-	return NewResult(data)
+	return u, nil
 }
 
-// loadFromEnvironment loads configuration from environment variables
-func loadFromEnvironment(config *Config) {
-	if secretURL := os.Getenv("MICV_SECRET_URL"); secretURL != "" {
-		config.SecretURL = secretURL
+// validateServiceURL checks that raw is an absolute http(s) URL with a host.
+// It is used for services that never receive the token, such as webhooks and
+// the OTLP collector.
+func validateServiceURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
 	}
 
-	if appURL := os.Getenv("MICV_APPLICATION_URL"); appURL != "" {
-		config.ApplicationURL = appURL
+	switch strings.ToLower(u.Scheme) {
+	case "https", "http":
+	case "":
+		return nil, fmt.Errorf("%q has no scheme (expected http or https)", raw)
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q in %q (expected http or https)", u.Scheme, raw)
 	}
 
-	if timeoutStr := os.Getenv("MICV_TIMEOUT"); timeoutStr != "" {
-		if timeout, err := strconv.Atoi(timeoutStr); err == nil && timeout > 0 {
-			config.Timeout = timeout
-		}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("%q has no host", raw)
 	}
+
+	return u, nil
 }
 
-// ValidateConfig validates the configuration
-func ValidateConfig(config *Config) error {
-	if config.SecretURL == "" {
-		return fmt.Errorf("secret URL is required")
-	}
+// normalizeEndpointURL trims whitespace and lower-cases the scheme and host;
+// unparseable values are returned trimmed for validation to report
+func normalizeEndpointURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return u.String()
+}
 
-	if config.ApplicationURL == "" {
-		return fmt.Errorf("application URL is required")
+// isLoopbackHost reports whether host is localhost or a loopback address
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
 
-	if config.Timeout <= 0 {
-		return fmt.Errorf("timeout must be positive")
+// endpointHostsDiffer reports whether the secret and application URLs point at different hosts
+func endpointHostsDiffer(config *Config) bool {
+	secret, err1 := url.Parse(config.SecretURL)
+	app, err2 := url.Parse(config.ApplicationURL)
+	if err1 != nil || err2 != nil {
+		return false
 	}
-
-	return nil
+	return !strings.EqualFold(secret.Hostname(), app.Hostname())
 }
 
-// handleGenerateFiles handles generation of config and/or data files
-func handleGenerateFiles(generateData, generateConfig bool) (*ConfigResult, error) {
+// generateFiles writes the sample data and/or config files
+func generateFiles(generateData, generateConfig bool) error {
 	var generatedFiles []string
 
 	if generateData {
@@ -370,8 +646,7 @@ func handleGenerateFiles(generateData, generateConfig bool) (*ConfigResult, erro
 		sampleData := createSampleApplicationData()
 		filename := "data.json"
 		if err := SaveApplicationData(sampleData, filename); err != nil {
-			fmt.Printf("❌ Error generating sample data file: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to generate sample data file: %w", err)
 		}
 		generatedFiles = append(generatedFiles, filename)
 		fmt.Printf("✅ Sample data.json file generated successfully!\n")
@@ -382,8 +657,7 @@ func handleGenerateFiles(generateData, generateConfig bool) (*ConfigResult, erro
 		sampleConfig := DefaultConfig()
 		filename := "config.json"
 		if err := SaveConfig(sampleConfig, filename); err != nil {
-			fmt.Printf("❌ Error generating sample config file: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to generate sample config file: %w", err)
 		}
 		generatedFiles = append(generatedFiles, filename)
 		fmt.Printf("✅ Sample config.json file generated successfully!\n")
@@ -395,7 +669,7 @@ func handleGenerateFiles(generateData, generateConfig bool) (*ConfigResult, erro
 		fmt.Printf("   - %s\n", file)
 	}
 
-	fmt.Printf("\n� Usage examples:\n")
+	fmt.Printf("\n💡 Usage examples:\n")
 	if generateData && generateConfig {
 		fmt.Printf("   %s --config config.json --data data.json\n", os.Args[0])
 	} else if generateData {
@@ -404,8 +678,7 @@ func handleGenerateFiles(generateData, generateConfig bool) (*ConfigResult, erro
 		fmt.Printf("   %s --config config.json\n", os.Args[0])
 	}
 
-	os.Exit(0)
-	return nil, nil // This line will never be reached due to os.Exit above
+	return nil
 }
 
 // createSampleApplicationData creates sample application data with realistic values
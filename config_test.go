@@ -552,3 +552,94 @@ func TestValidateApplicationData(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateEndpointURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		allowInsecure bool
+		expectError   bool
+	}{
+		{"https", "https://au.mitimes.com/careers/apply", false, false},
+		{"http loopback", "http://localhost:8081/apply", false, false},
+		{"http loopback ip", "http://127.0.0.1:8081/apply", false, false},
+		{"http loopback ipv6", "http://[::1]:8081/apply", false, false},
+		{"http remote", "http://au.mitimes.com/careers/apply", false, true},
+		{"http remote allowed", "http://au.mitimes.com/careers/apply", true, false},
+		{"typo scheme", "htps://au.mitimes.com/careers/apply", false, true},
+		{"missing scheme", "au.mitimes.com/careers/apply", false, true},
+		{"missing host", "https:///careers/apply", false, true},
+		{"unparseable", "https://au.mitimes.com:port/apply", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateEndpointURL(tt.url, tt.allowInsecure)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error for %s", tt.url)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error for %s: %v", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigURLs(t *testing.T) {
+	config := DefaultConfig()
+	config.ApplicationURL = "htps://au.mitimes.com/careers/apply"
+	if err := ValidateConfig(config); err == nil || !strings.Contains(err.Error(), "application URL") {
+		t.Errorf("Expected invalid application URL error, got %v", err)
+	}
+
+	deps := NewMockDependencies()
+	deps.config.SecretURL = "http://au.mitimes.com/careers/apply/secret"
+	err := NewConfigService(deps).ValidateConfig()
+	appErr, ok := err.(*AppError)
+	if !ok || appErr.Code != ErrCodeConfig {
+		t.Fatalf("Expected %s error, got %v", ErrCodeConfig, err)
+	}
+
+	deps.config.AllowInsecure = true
+	if err := NewConfigService(deps).ValidateConfig(); err != nil {
+		t.Errorf("Expected --allow-insecure to permit plain HTTP, got %v", err)
+	}
+}
+
+func TestConfigServiceValidatesFullConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"webhook URL", func(c *Config) { c.Notifications.Webhooks = map[string]string{"slack": "ftp://hooks.example.com"} }},
+		{"OTLP endpoint", func(c *Config) { c.Tracing.OTLPEndpoint = "not a url" }},
+		{"retry", func(c *Config) { c.Retry.MaxAttempts = -1 }},
+		{"TLS version", func(c *Config) { c.TLS.MinVersion = "0.9" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := NewMockDependencies()
+			tt.modify(deps.config)
+			if err := NewConfigService(deps).ValidateConfig(); err == nil {
+				t.Errorf("Expected invalid %s to be rejected before submitting", tt.name)
+			}
+		})
+	}
+}
+
+func TestNormalizeEndpointURL(t *testing.T) {
+	got := normalizeEndpointURL("  HTTPS://AU.MiTimes.com/careers/Apply ")
+	if got != "https://au.mitimes.com/careers/Apply" {
+		t.Errorf("Expected normalized URL, got %s", got)
+	}
+
+	config := DefaultConfig()
+	config.ApplicationURL = "https://other.example.com/apply"
+	if !endpointHostsDiffer(config) {
+		t.Error("Expected different hosts to be detected")
+	}
+	if endpointHostsDiffer(DefaultConfig()) {
+		t.Error("Expected default URLs to share a host")
+	}
+}
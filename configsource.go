@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// SourceDefault marks values that come from DefaultConfig
+const SourceDefault = "default"
+
+// configFlagFields maps command-line flags to the config fields they set
+var configFlagFields = map[string]string{
+	"secret-url":           "secret_url",
+	"app-url":              "application_url",
+	"timeout":              "timeout_seconds",
+	"proxy":                "proxy_url",
+	"ca-cert":              "tls.ca_file",
+	"client-cert":          "tls.cert_file",
+	"client-key":           "tls.key_file",
+	"tls-min-version":      "tls.min_version",
+	"insecure-skip-verify": "tls.insecure_skip_verify",
+	"allow-insecure":       "allow_insecure",
+	"token-cache-ttl":      "token_cache_ttl_seconds",
+	"auth-scheme":          "auth.scheme",
+	"secret-mode":          "secret_response.mode",
+	"secret-path":          "secret_response.path",
+	"secret-header":        "secret_response.header",
+	"rate-limit":           "rate_limits.*.requests_per_second",
+	"rate-burst":           "rate_limits.*.burst",
+	"metrics-file":         "metrics.file",
+	"metrics-addr":         "metrics.addr",
+	"trace-file":           "tracing.file",
+	"otlp-endpoint":        "tracing.otlp_endpoint",
+}
+
+// ConfigSources records where each effective configuration value came from
+type ConfigSources struct {
+	fields   map[string]string
+	snapshot map[string]interface{}
+}
+
+// NewConfigSources starts tracking from the given default configuration
+func NewConfigSources(config *Config) *ConfigSources {
+	sources := &ConfigSources{
+		fields:   make(map[string]string),
+		snapshot: flattenConfig(config),
+	}
+	for path := range sources.snapshot {
+		sources.fields[path] = SourceDefault
+	}
+	return sources
+}
+
+// Track attributes to source every field changed since the last call and
+// every field in set, even when the value it was set to is unchanged
+func (s *ConfigSources) Track(config *Config, source string, set []string) {
+	for _, path := range s.changed(config) {
+		s.fields[path] = source
+	}
+	for _, path := range set {
+		s.record(path, source)
+	}
+}
+
+// TrackFlags attributes fields to the command-line flag of fs that set them
+func (s *ConfigSources) TrackFlags(config *Config, fs *flag.FlagSet) {
+	for _, path := range s.changed(config) {
+		s.fields[path] = "command line"
+	}
+
+	// Values are compared with the defaults rather than visited, so that
+	// options given after the command name are included
+	rateLimitSet := false
+	if f := fs.Lookup("rate-limit"); f != nil {
+		rateLimitSet = f.Value.String() != f.DefValue
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		field, ok := configFlagFields[f.Name]
+		if !ok {
+			return
+		}
+		// --rate-burst only applies together with --rate-limit
+		if f.Name == "rate-burst" && !rateLimitSet {
+			return
+		}
+		if f.Name == "rate-burst" || f.Value.String() != f.DefValue {
+			s.record(field, "flag --"+f.Name)
+		}
+	})
+}
+
+// TrackEnvironment attributes fields to the variable that set them
+func (s *ConfigSources) TrackEnvironment(config *Config) {
+	for _, path := range s.changed(config) {
+		s.fields[path] = "environment"
+	}
+	for _, binding := range configEnvBindings {
+		if strings.TrimSpace(os.Getenv(binding.name)) != "" {
+			s.record(binding.field, "env "+binding.name)
+		}
+	}
+}
+
+// record attributes the field at path to source if the field exists
+func (s *ConfigSources) record(path, source string) {
+	if _, ok := s.snapshot[path]; ok {
+		s.fields[path] = source
+	}
+}
+
+// Source returns the source of the field at path
+func (s *ConfigSources) Source(path string) string {
+	if s == nil {
+		return SourceDefault
+	}
+	if source, ok := s.fields[path]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// changed returns the fields whose value differs from the last snapshot and
+// takes a new snapshot
+func (s *ConfigSources) changed(config *Config) []string {
+	current := flattenConfig(config)
+
+	var paths []string
+	for path, value := range current {
+		if previous, ok := s.snapshot[path]; !ok || previous != value {
+			paths = append(paths, path)
+		}
+	}
+	s.snapshot = current
+
+	sort.Strings(paths)
+	return paths
+}
+
+// configFileFields returns the fields set by filename and, when profile is
+// given, by the profile and its ancestors
+func configFileFields(filename, profile string) ([]string, error) {
+	data, err := readConfigDocument(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode config file: %w", err)
+	}
+	delete(document, "profiles")
+	leaves := make(map[string]interface{})
+	flattenJSON("", document, leaves)
+
+	if profile != "" {
+		var file configProfiles
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to decode config profiles: %w", err)
+		}
+		chain, err := resolveProfileChain(file.Profiles, profile)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range chain {
+			var values interface{}
+			if err := json.Unmarshal(file.Profiles[name], &values); err != nil {
+				return nil, fmt.Errorf("failed to decode profile %q: %w", name, err)
+			}
+			flattenJSON("", values, leaves)
+		}
+	}
+
+	fields := make([]string, 0, len(leaves))
+	for path := range leaves {
+		fields = append(fields, path)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// fileSource describes a configuration file, and profile, as a value source
+func fileSource(filename, profile string) string {
+	if profile != "" {
+		return fmt.Sprintf("file %s (profile %s)", filename, profile)
+	}
+	return "file " + filename
+}
+
+// flattenConfig returns the leaf values of config keyed by JSON field path
+func flattenConfig(config *Config) map[string]interface{} {
+	leaves := make(map[string]interface{})
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return leaves
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return leaves
+	}
+
+	flattenJSON("", tree, leaves)
+	return leaves
+}
+
+// flattenJSON collects the scalar values below path
+func flattenJSON(path string, value interface{}, leaves map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenJSON(childPath, child, leaves)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenJSON(path+"["+strconv.Itoa(i)+"]", child, leaves)
+		}
+	default:
+		leaves[path] = value
+	}
+}
+
+// printConfigExplanation writes every effective field with its value and source
+func printConfigExplanation(w io.Writer, config *Config, sources *ConfigSources) {
+	leaves := flattenConfig(config)
+	paths := make([]string, 0, len(leaves))
+	for path := range leaves {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tVALUE\tSOURCE")
+	for _, path := range paths {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", path, formatDiffValue(leaves[path]), sources.Source(path))
+	}
+	tw.Flush()
+}
+
+// defineConfigFlags defines the options of the config command on flags
+func defineConfigFlags(flags *flag.FlagSet) (explain *bool) {
+	return flags.Bool("explain", false, "Show where each value came from")
+}
+
+// runConfigCommand handles `config show [--explain]`
+func runConfigCommand(configResult *ConfigResult, args []string) error {
+	if len(args) == 0 || args[0] != "show" {
+		return fmt.Errorf("unknown config command (expected show)")
+	}
+
+	flags := flag.NewFlagSet("config show", flag.ContinueOnError)
+	explain := defineConfigFlags(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if !*explain {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(configResult.Config)
+	}
+
+	printConfigExplanation(os.Stdout, configResult.Config, configResult.Sources)
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func loadConfigWithArgs(t *testing.T, args ...string) *ConfigResult {
+	t.Helper()
+
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+	os.Args = append([]string{"micv"}, args...)
+
+	configResult, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	return configResult
+}
+
+func TestConfigSources(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	configContent := `{"secret_url": "https://file.test.com/secret", "timeout_seconds": 120}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	t.Setenv("MICV_SECRET_URL", "https://env.test.com/secret")
+	configResult := loadConfigWithArgs(t, "--config", configFile, "--secret-url", "https://flag.test.com/secret", "--proxy", "http://proxy:3128")
+
+	expected := map[string]string{
+		"application_url": SourceDefault,
+		"timeout_seconds": "file " + configFile,
+		"proxy_url":       "flag --proxy",
+		"secret_url":      "env MICV_SECRET_URL",
+	}
+	for path, source := range expected {
+		if got := configResult.Sources.Source(path); got != source {
+			t.Errorf("Expected source of %s to be %q, got %q", path, source, got)
+		}
+	}
+	if configResult.Config.SecretURL != "https://env.test.com/secret" {
+		t.Errorf("Expected environment to override flags by default, got '%s'", configResult.Config.SecretURL)
+	}
+
+	var out bytes.Buffer
+	printConfigExplanation(&out, configResult.Config, configResult.Sources)
+	if !strings.Contains(out.String(), "env MICV_SECRET_URL") {
+		t.Errorf("Expected explanation to include the environment source, got:\n%s", out.String())
+	}
+}
+
+func TestConfigSourcesRecordUnchangedValues(t *testing.T) {
+	defaults := DefaultConfig()
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	configContent := `{
+		"application_url": "` + defaults.ApplicationURL + `",
+		"proxy_url": "http://proxy:3128",
+		"profiles": {"prod": {"timeout_seconds": ` + strconv.Itoa(defaults.Timeout) + `}}
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	// Every layer sets a value equal to the one it overrides
+	t.Setenv("MICV_PROXY_URL", "http://proxy:3128")
+	configResult := loadConfigWithArgs(t, "--config", configFile, "--profile", "prod", "--proxy", "http://proxy:3128")
+
+	expected := map[string]string{
+		"application_url": "file " + configFile + " (profile prod)",
+		"timeout_seconds": "file " + configFile + " (profile prod)",
+		"proxy_url":       "env MICV_PROXY_URL",
+		"secret_url":      SourceDefault,
+	}
+	for path, source := range expected {
+		if got := configResult.Sources.Source(path); got != source {
+			t.Errorf("Expected source of %s to be %q, got %q", path, source, got)
+		}
+	}
+
+	configResult = loadConfigWithArgs(t, "--flags-over-env", "--config", configFile, "--proxy", "http://proxy:3128")
+	if got := configResult.Sources.Source("proxy_url"); got != "flag --proxy" {
+		t.Errorf("Expected proxy_url source to be the flag, got %q", got)
+	}
+}
+
+func TestFlagsOverEnvPrecedence(t *testing.T) {
+	t.Setenv("MICV_SECRET_URL", "https://env.test.com/secret")
+	configResult := loadConfigWithArgs(t, "--flags-over-env", "--secret-url", "https://flag.test.com/secret")
+
+	if configResult.Config.SecretURL != "https://flag.test.com/secret" {
+		t.Errorf("Expected flag to override environment, got '%s'", configResult.Config.SecretURL)
+	}
+	if got := configResult.Sources.Source("secret_url"); got != "flag --secret-url" {
+		t.Errorf("Expected secret_url source to be the flag, got %q", got)
+	}
+}
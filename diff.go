@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Kinds of field changes reported by DiffPayloads
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// FieldChange describes a difference at a single field path
+type FieldChange struct {
+	Path   string
+	Kind   string
+	Before interface{}
+	After  interface{}
+}
+
+// DiffPayloads compares two decoded JSON values and returns the changed field paths
+func DiffPayloads(before, after interface{}) []FieldChange {
+	var changes []FieldChange
+	diffValues("", before, after, &changes)
+	return changes
+}
+
+// diffValues recursively compares JSON values rooted at path
+func diffValues(path string, before, after interface{}, changes *[]FieldChange) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			diffObjects(path, b, a, changes)
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			diffArrays(path, b, a, changes)
+			return
+		}
+	default:
+		if before == after {
+			return
+		}
+	}
+
+	*changes = append(*changes, FieldChange{Path: path, Kind: ChangeModified, Before: before, After: after})
+}
+
+// diffObjects compares object fields in sorted key order
+func diffObjects(path string, before, after map[string]interface{}, changes *[]FieldChange) {
+	keys := make(map[string]bool)
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+
+		b, inBefore := before[key]
+		a, inAfter := after[key]
+		switch {
+		case !inBefore:
+			*changes = append(*changes, FieldChange{Path: childPath, Kind: ChangeAdded, After: a})
+		case !inAfter:
+			*changes = append(*changes, FieldChange{Path: childPath, Kind: ChangeRemoved, Before: b})
+		default:
+			diffValues(childPath, b, a, changes)
+		}
+	}
+}
+
+// diffArrays compares array elements by index
+func diffArrays(path string, before, after []interface{}, changes *[]FieldChange) {
+	for i := 0; i < len(before) || i < len(after); i++ {
+		childPath := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= len(before):
+			*changes = append(*changes, FieldChange{Path: childPath, Kind: ChangeAdded, After: after[i]})
+		case i >= len(after):
+			*changes = append(*changes, FieldChange{Path: childPath, Kind: ChangeRemoved, Before: before[i]})
+		default:
+			diffValues(childPath, before[i], after[i], changes)
+		}
+	}
+}
+
+// decodePayload converts application data into a generic JSON value
+func decodePayload(appData ApplicationData) (interface{}, error) {
+	payload, err := canonicalPayload(appData)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRawPayload(payload)
+}
+
+// decodeRawPayload decodes stored JSON into a generic JSON value
+func decodeRawPayload(payload []byte) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return value, nil
+}
+
+// loadRenderedApplicationData loads a data file and renders its templates
+func loadRenderedApplicationData(filename string, vars map[string]string) (ApplicationData, error) {
+	appData, err := LoadApplicationData(filename)
+	if err != nil {
+		return ApplicationData{}, err
+	}
+
+	rendered, err := RenderApplicationData(*appData, vars)
+	if err != nil {
+		return ApplicationData{}, fmt.Errorf("failed to render %s: %w", filename, err)
+	}
+	return rendered, nil
+}
+
+// formatDiffValue renders a JSON value on a single line
+func formatDiffValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// printDiff writes changes in a unified, field-path oriented format
+func printDiff(w io.Writer, changes []FieldChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "✅ No differences")
+		return
+	}
+
+	for _, change := range changes {
+		switch change.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(w, "+ %s: %s\n", change.Path, formatDiffValue(change.After))
+		case ChangeRemoved:
+			fmt.Fprintf(w, "- %s: %s\n", change.Path, formatDiffValue(change.Before))
+		default:
+			fmt.Fprintf(w, "~ %s: %s → %s\n", change.Path, formatDiffValue(change.Before), formatDiffValue(change.After))
+		}
+	}
+	fmt.Fprintf(w, "\n%d field(s) changed\n", len(changes))
+}
+
+// defineDiffFlags defines the options of the diff command on flags
+func defineDiffFlags(flags *flag.FlagSet) (dataFile, againstFile *string) {
+	dataFile = flags.String("data", "", "Data file about to be submitted")
+	againstFile = flags.String("against", "", "Data file to compare with (default: last successful submission)")
+	return dataFile, againstFile
+}
+
+// runDiffCommand handles `diff --data file [--against file]`. Data files are
+// rendered with vars, as submit renders them, before they are compared.
+func runDiffCommand(config *Config, args []string, vars map[string]string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	dataFile, againstFile := defineDiffFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dataFile == "" {
+		return fmt.Errorf("--data is required")
+	}
+
+	current, err := loadRenderedApplicationData(*dataFile, vars)
+	if err != nil {
+		return err
+	}
+	after, err := decodePayload(current)
+	if err != nil {
+		return err
+	}
+
+	var before interface{}
+	if *againstFile != "" {
+		previous, err := loadRenderedApplicationData(*againstFile, vars)
+		if err != nil {
+			return err
+		}
+		if before, err = decodePayload(previous); err != nil {
+			return err
+		}
+		fmt.Printf("🔍 Comparing %s against %s\n\n", *dataFile, *againstFile)
+	} else {
+		store, err := newSubmissionStoreFromConfig(config)
+		if err != nil {
+			return err
+		}
+		record, ok, err := store.LatestSucceeded(config.ApplicationURL, current.Email)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("no successful submission by %s to %s recorded", current.Email, config.ApplicationURL)
+		}
+		if before, err = decodeRawPayload(record.Payload); err != nil {
+			return err
+		}
+		fmt.Printf("🔍 Comparing %s against submission of %s\n\n", *dataFile, record.SubmittedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	printDiff(os.Stdout, DiffPayloads(before, after))
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffPayloads(t *testing.T) {
+	before := map[string]interface{}{
+		"name":      "John Doe",
+		"job_title": "Software Engineer",
+		"extra_information": map[string]interface{}{
+			"location": "Australia",
+			"experience": map[string]interface{}{
+				"key_projects": []interface{}{"API gateway", "Billing", "Search"},
+			},
+		},
+	}
+	after := map[string]interface{}{
+		"name":      "John Doe",
+		"job_title": "Senior Software Engineer",
+		"extra_information": map[string]interface{}{
+			"availability": "Immediate",
+			"experience": map[string]interface{}{
+				"key_projects": []interface{}{"API gateway", "Billing", "Recommendations", "Payments"},
+			},
+		},
+	}
+
+	changes := DiffPayloads(before, after)
+	expected := []FieldChange{
+		{Path: "extra_information.availability", Kind: ChangeAdded},
+		{Path: "extra_information.experience.key_projects[2]", Kind: ChangeModified},
+		{Path: "extra_information.experience.key_projects[3]", Kind: ChangeAdded},
+		{Path: "extra_information.location", Kind: ChangeRemoved},
+		{Path: "job_title", Kind: ChangeModified},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+	}
+	for i, want := range expected {
+		if changes[i].Path != want.Path || changes[i].Kind != want.Kind {
+			t.Errorf("Change %d: expected %s %s, got %s %s", i, want.Kind, want.Path, changes[i].Kind, changes[i].Path)
+		}
+	}
+
+	if len(DiffPayloads(before, before)) != 0 {
+		t.Error("Expected no changes for identical payloads")
+	}
+}
+
+func TestLatestSucceededSubmission(t *testing.T) {
+	store := NewSubmissionStore(filepath.Join(t.TempDir(), "submissions.json"))
+	url := "https://example.com/apply"
+	now := time.Now()
+
+	john := json.RawMessage(`{"email":"john@example.com"}`)
+	jane := json.RawMessage(`{"email":"jane@example.com"}`)
+
+	records := []SubmissionRecord{
+		{IdempotencyKey: "old", URL: url, Outcome: SubmissionSucceeded, Payload: john, SubmittedAt: now.Add(-2 * time.Hour)},
+		{IdempotencyKey: "new", URL: url, Outcome: SubmissionSucceeded, Payload: john, SubmittedAt: now.Add(-1 * time.Hour)},
+		{IdempotencyKey: "failed", URL: url, Outcome: SubmissionFailed, Payload: john, SubmittedAt: now},
+		{IdempotencyKey: "other", URL: "https://other.example.com/apply", Outcome: SubmissionSucceeded, Payload: john, SubmittedAt: now},
+		{IdempotencyKey: "jane", URL: url, Outcome: SubmissionSucceeded, Payload: jane, SubmittedAt: now},
+	}
+	for _, record := range records {
+		if err := store.Put(record); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	latest, ok, _ := store.LatestSucceeded(url, "John@Example.com")
+	if !ok {
+		t.Fatal("Expected a successful submission")
+	}
+	if latest.IdempotencyKey != "new" {
+		t.Errorf("Expected latest successful record, got %s", latest.IdempotencyKey)
+	}
+
+	if _, ok, _ := store.LatestSucceeded("https://unknown.example.com/apply", "john@example.com"); ok {
+		t.Error("Expected no submission for an unknown URL")
+	}
+
+	if latest, ok, _ := store.LatestSucceeded(url, "jane@example.com"); !ok || latest.IdempotencyKey != "jane" {
+		t.Errorf("Expected the other applicant's own submission, got %s", latest.IdempotencyKey)
+	}
+	if _, ok, _ := store.LatestSucceeded(url, "nobody@example.com"); ok {
+		t.Error("Expected no submission for an unknown applicant")
+	}
+
+	var nilStore *SubmissionStore
+	if _, ok, _ := nilStore.LatestSucceeded(url, "john@example.com"); ok {
+		t.Error("Expected nil store to report no submission")
+	}
+}
+
+func TestDiffRendersTemplates(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.SubmissionStoreFile = filepath.Join(dir, "submissions.json")
+
+	submitted := ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Backend Engineer"}
+	payload, err := canonicalPayload(submitted)
+	if err != nil {
+		t.Fatalf("canonicalPayload failed: %v", err)
+	}
+	store := NewSubmissionStore(config.SubmissionStoreFile)
+	store.Put(SubmissionRecord{IdempotencyKey: "key", URL: config.ApplicationURL, Outcome: SubmissionSucceeded, Payload: payload, SubmittedAt: time.Now()})
+
+	dataFile := filepath.Join(dir, "applicant.json")
+	data := `{"name": "John Doe", "email": "{{var \"email\"}}", "job_title": "Backend Engineer"}`
+	if err := os.WriteFile(dataFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	vars := map[string]string{"email": "john@example.com"}
+	if err := runDiffCommand(config, []string{"--data", dataFile}, vars); err != nil {
+		t.Errorf("Expected the rendered data file to match the submission, got %v", err)
+	}
+	if err := runDiffCommand(config, []string{"--data", dataFile, "--against", dataFile}, vars); err != nil {
+		t.Errorf("Expected rendered data files to be compared, got %v", err)
+	}
+	if err := runDiffCommand(config, []string{"--data", dataFile}, nil); err == nil || !strings.Contains(err.Error(), "email") {
+		t.Errorf("Expected a render error without the variable, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// localConfigFile is the configuration file looked up in the working directory
+const localConfigFile = "micv.json"
+
+// localDataFile is the data file looked up in the working directory; it is the
+// file written by --generate-data-json
+const localDataFile = "data.json"
+
+// userConfigDirs returns the per-user micv configuration directories, in order:
+// $XDG_CONFIG_HOME/micv followed by ~/.config/micv
+func userConfigDirs() []string {
+	var dirs []string
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		dirs = append(dirs, filepath.Join(xdg, "micv"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config", "micv"))
+	}
+	return dedupePaths(dirs)
+}
+
+// configSearchPaths returns the candidate configuration files in search order
+func configSearchPaths() []string {
+	paths := []string{localConfigFile}
+	for _, dir := range userConfigDirs() {
+		for _, name := range []string{"config.json", "config.yaml", "config.yml"} {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	return paths
+}
+
+// dataSearchPaths returns the candidate data files in search order
+func dataSearchPaths() []string {
+	paths := []string{localDataFile}
+	for _, dir := range userConfigDirs() {
+		paths = append(paths, filepath.Join(dir, "data.json"))
+	}
+	return paths
+}
+
+// discoverConfigFile returns the first configuration file that exists, or ""
+func discoverConfigFile() string {
+	return firstExistingFile(configSearchPaths())
+}
+
+// discoverDataFile returns the first data file that exists, or ""
+func discoverDataFile() string {
+	return firstExistingFile(dataSearchPaths())
+}
+
+// firstExistingFile returns the first path that is a regular file
+func firstExistingFile(paths []string) string {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+// dedupePaths removes repeated paths while keeping their order
+func dedupePaths(paths []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, path := range paths {
+		clean := filepath.Clean(path)
+		if seen[clean] {
+			continue
+		}
+		seen[clean] = true
+		unique = append(unique, clean)
+	}
+	return unique
+}
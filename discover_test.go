@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverConfigFile(t *testing.T) {
+	workDir := t.TempDir()
+	xdgDir := t.TempDir()
+	t.Chdir(workDir)
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+	t.Setenv("HOME", t.TempDir())
+
+	if got := discoverConfigFile(); got != "" {
+		t.Fatalf("Expected no configuration file, got %s", got)
+	}
+
+	// XDG configuration directory, YAML only
+	userConfig := filepath.Join(xdgDir, "micv", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(userConfig), 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	if err := os.WriteFile(userConfig, []byte("timeout_seconds: 15\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if got := discoverConfigFile(); got != userConfig {
+		t.Errorf("Expected %s, got %s", userConfig, got)
+	}
+
+	// JSON wins over YAML in the same directory
+	userJSON := filepath.Join(xdgDir, "micv", "config.json")
+	if err := os.WriteFile(userJSON, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if got := discoverConfigFile(); got != userJSON {
+		t.Errorf("Expected %s, got %s", userJSON, got)
+	}
+
+	// The working directory wins over the user configuration directory
+	if err := os.WriteFile(localConfigFile, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if got := discoverConfigFile(); got != localConfigFile {
+		t.Errorf("Expected %s, got %s", localConfigFile, got)
+	}
+}
+
+func TestLoadConfigDiscovery(t *testing.T) {
+	xdgDir := t.TempDir()
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := filepath.Join(xdgDir, "micv")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	configContent := "secret_url: https://yaml.test.com/secret\ntimeout_seconds: 45\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "data.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	configResult := loadConfigWithArgs(t)
+	if !configResult.ConfigDiscovered {
+		t.Error("Expected configuration file to be discovered")
+	}
+	if configResult.Config.SecretURL != "https://yaml.test.com/secret" || configResult.Config.Timeout != 45 {
+		t.Errorf("Expected values from discovered YAML file, got %+v", configResult.Config)
+	}
+	if configResult.DataFile != filepath.Join(configDir, "data.json") || !configResult.DataDiscovered {
+		t.Errorf("Expected discovered data file, got '%s' (discovered=%v)", configResult.DataFile, configResult.DataDiscovered)
+	}
+
+	// Positional applicant arguments disable data file discovery
+	configResult = loadConfigWithArgs(t, "John Doe", "john@example.com", "Software Engineer")
+	if configResult.DataFile != "" {
+		t.Errorf("Expected no data file with positional arguments, got '%s'", configResult.DataFile)
+	}
+
+	// An explicit data file is not reported as discovered
+	configResult = loadConfigWithArgs(t, "--data", "applicant.json")
+	if configResult.DataFile != "applicant.json" || configResult.DataDiscovered {
+		t.Errorf("Expected explicit data file, got '%s' (discovered=%v)", configResult.DataFile, configResult.DataDiscovered)
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// CheckStatus is the outcome of a doctor check
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// Thresholds used by the doctor checks
+const (
+	certificateExpiryWarning = 14 * 24 * time.Hour
+	clockSkewWarning         = 30 * time.Second
+	clockSkewFailure         = 5 * time.Minute
+)
+
+// DoctorCheck is a single line of the doctor checklist
+type DoctorCheck struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+}
+
+// Doctor diagnoses connectivity to the configured endpoints
+type Doctor struct {
+	config    *Config
+	client    HTTPClient
+	extractor SecretExtractor
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	now       func() time.Time
+}
+
+// NewDoctor creates a Doctor using the proxy, TLS and secret settings in config
+func NewDoctor(config *Config) (*Doctor, error) {
+	client, err := NewHTTPClientFromConfig(config)
+	if err != nil {
+		return nil, WrapConfigError(err, "tls")
+	}
+	tlsConfig, err := newTLSConfig(config.TLS)
+	if err != nil {
+		return nil, WrapConfigError(err, "tls")
+	}
+	extractor, err := NewSecretExtractor(config.SecretResponse)
+	if err != nil {
+		return nil, WrapConfigError(err, "secret_response")
+	}
+
+	return &Doctor{
+		config:    config,
+		client:    client,
+		extractor: extractor,
+		tlsConfig: tlsConfig,
+		timeout:   time.Duration(config.Timeout) * time.Second,
+		now:       time.Now,
+	}, nil
+}
+
+// Run performs every check and returns the checklist in order
+func (d *Doctor) Run(ctx context.Context) []DoctorCheck {
+	proxyCheck, proxied := d.checkProxy(ctx)
+	checks := []DoctorCheck{proxyCheck}
+
+	// Connection checks run once per host, HTTP checks once per URL
+	seenHosts := make(map[string]bool)
+	var serverDate string
+	for _, endpoint := range []struct{ label, rawURL string }{
+		{"secret endpoint", d.config.SecretURL},
+		{"application endpoint", d.config.ApplicationURL},
+	} {
+		u, err := url.Parse(endpoint.rawURL)
+		if err != nil || u.Host == "" {
+			checks = append(checks, DoctorCheck{Name: "URL " + endpoint.label, Status: CheckFail, Detail: fmt.Sprintf("invalid URL %q", endpoint.rawURL)})
+			continue
+		}
+
+		address := endpointAddress(u)
+		if !seenHosts[address] {
+			seenHosts[address] = true
+			checks = append(checks, d.checkConnection(ctx, u, address, proxied)...)
+		}
+
+		check, date := d.checkReachability(ctx, endpoint.label, endpoint.rawURL)
+		checks = append(checks, check)
+		if serverDate == "" {
+			serverDate = date
+		}
+	}
+
+	checks = append(checks, d.checkClockSkew(serverDate))
+	checks = append(checks, d.checkSecretResponse(ctx))
+	return checks
+}
+
+// endpointAddress returns the host:port an endpoint URL connects to
+func endpointAddress(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// checkProxy reports the proxy in use and whether it accepts connections
+func (d *Doctor) checkProxy(ctx context.Context) (DoctorCheck, bool) {
+	check := DoctorCheck{Name: "Proxy"}
+
+	source := "configured"
+	proxyURL, err := url.Parse(d.config.ProxyURL)
+	if d.config.ProxyURL == "" {
+		// The transport falls back to HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+		source = "environment"
+		proxyURL, err = nil, nil
+		if req, reqErr := http.NewRequest(http.MethodGet, d.config.SecretURL, nil); reqErr == nil {
+			proxyURL, err = http.ProxyFromEnvironment(req)
+		}
+	}
+	if err != nil || (proxyURL != nil && proxyURL.Host == "") {
+		check.Status, check.Detail = CheckFail, fmt.Sprintf("invalid %s proxy URL", source)
+		return check, true
+	}
+	if proxyURL == nil {
+		check.Status, check.Detail = CheckPass, "no proxy configured, connecting directly"
+		return check, false
+	}
+
+	if err := d.dial(ctx, endpointAddress(proxyURL)); err != nil {
+		check.Status, check.Detail = CheckFail, fmt.Sprintf("cannot connect to %s proxy %s: %v", source, proxyURL.Host, err)
+		return check, true
+	}
+	check.Status, check.Detail = CheckPass, fmt.Sprintf("using %s proxy %s", source, proxyURL.Redacted())
+	return check, true
+}
+
+// checkConnection checks DNS resolution, TCP connect and, for HTTPS, the
+// server certificate; through a proxy, direct failures are only warnings
+func (d *Doctor) checkConnection(ctx context.Context, u *url.URL, address string, proxied bool) []DoctorCheck {
+	failure := CheckFail
+	note := ""
+	if proxied {
+		failure = CheckWarn
+		note = " (requests go through the proxy)"
+	}
+
+	host := u.Hostname()
+	dnsCheck := DoctorCheck{Name: "DNS " + host}
+	lookupCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	addrs, err := net.DefaultResolver.LookupHost(lookupCtx, host)
+	cancel()
+	if err != nil {
+		dnsCheck.Status, dnsCheck.Detail = failure, err.Error()+note
+		return []DoctorCheck{dnsCheck}
+	}
+	dnsCheck.Status, dnsCheck.Detail = CheckPass, "resolves to "+strings.Join(addrs, ", ")
+
+	tcpCheck := DoctorCheck{Name: "TCP " + address}
+	start := time.Now()
+	if err := d.dial(ctx, address); err != nil {
+		tcpCheck.Status, tcpCheck.Detail = failure, err.Error()+note
+		return []DoctorCheck{dnsCheck, tcpCheck}
+	}
+	tcpCheck.Status, tcpCheck.Detail = CheckPass, fmt.Sprintf("connected in %s", time.Since(start).Round(time.Millisecond))
+
+	checks := []DoctorCheck{dnsCheck, tcpCheck}
+	if u.Scheme == "https" {
+		tlsCheck := d.checkCertificate(ctx, host, address)
+		if tlsCheck.Status == CheckFail {
+			tlsCheck.Status, tlsCheck.Detail = failure, tlsCheck.Detail+note
+		}
+		checks = append(checks, tlsCheck)
+	}
+	return checks
+}
+
+// dial opens and closes a TCP connection to address
+func (d *Doctor) dial(ctx context.Context, address string) error {
+	dialer := &net.Dialer{Timeout: d.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkCertificate performs a TLS handshake and checks the server certificate
+func (d *Doctor) checkCertificate(ctx context.Context, host, address string) DoctorCheck {
+	check := DoctorCheck{Name: "TLS " + address}
+
+	tlsConfig := d.tlsConfig.Clone()
+	tlsConfig.ServerName = host
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: d.timeout}, Config: tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		check.Status, check.Detail = CheckFail, err.Error()
+		return check
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		check.Status, check.Detail = CheckFail, "server presented no certificate"
+		return check
+	}
+	return d.certificateCheck(check, state.Version, state.PeerCertificates[0])
+}
+
+// certificateCheck rates the leaf certificate of a completed handshake by its expiry
+func (d *Doctor) certificateCheck(check DoctorCheck, version uint16, cert *x509.Certificate) DoctorCheck {
+	remaining := cert.NotAfter.Sub(d.now())
+	detail := fmt.Sprintf("%s, issued by %q, valid until %s", tls.VersionName(version), cert.Issuer.CommonName, cert.NotAfter.Format(time.DateOnly))
+
+	switch {
+	case remaining <= 0:
+		check.Status, check.Detail = CheckFail, "certificate expired on "+cert.NotAfter.Format(time.DateOnly)
+	case d.config.TLS.InsecureSkipVerify:
+		check.Status, check.Detail = CheckWarn, "certificate verification is disabled (insecure_skip_verify); "+detail
+	case remaining < certificateExpiryWarning:
+		check.Status, check.Detail = CheckWarn, fmt.Sprintf("certificate expires in %d days; %s", int(remaining.Hours()/24), detail)
+	default:
+		check.Status, check.Detail = CheckPass, detail
+	}
+	return check
+}
+
+// checkReachability sends a HEAD request, falling back to OPTIONS when HEAD is
+// not allowed, and returns the server Date header
+func (d *Doctor) checkReachability(ctx context.Context, label, rawURL string) (DoctorCheck, string) {
+	check := DoctorCheck{Name: "HTTP " + label}
+
+	var resp *http.Response
+	for _, method := range []string{http.MethodHead, http.MethodOptions} {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+		if err != nil {
+			check.Status, check.Detail = CheckFail, err.Error()
+			return check, ""
+		}
+		resp, err = d.client.Do(req)
+		if err != nil {
+			check.Status, check.Detail = CheckFail, err.Error()
+			return check, ""
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented {
+			break
+		}
+	}
+
+	method := resp.Request.Method
+	switch {
+	case resp.StatusCode >= 500:
+		check.Status = CheckWarn
+	default:
+		// Any other response, including 4xx, shows the endpoint is reachable
+		check.Status = CheckPass
+	}
+	check.Detail = fmt.Sprintf("%s %s", method, resp.Status)
+	return check, resp.Header.Get("Date")
+}
+
+// checkClockSkew compares the local clock with a server Date header
+func (d *Doctor) checkClockSkew(serverDate string) DoctorCheck {
+	check := DoctorCheck{Name: "Clock skew"}
+	if serverDate == "" {
+		check.Status, check.Detail = CheckWarn, "no Date header received from the endpoints"
+		return check
+	}
+	date, err := http.ParseTime(serverDate)
+	if err != nil {
+		check.Status, check.Detail = CheckWarn, fmt.Sprintf("invalid Date header %q", serverDate)
+		return check
+	}
+
+	skew := d.now().Sub(date).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	switch {
+	case skew > clockSkewFailure:
+		check.Status = CheckFail
+	case skew > clockSkewWarning:
+		check.Status = CheckWarn
+	default:
+		check.Status = CheckPass
+	}
+	check.Detail = fmt.Sprintf("local clock differs from the server by %s", skew)
+	return check
+}
+
+// checkSecretResponse fetches a token and checks that the response parses
+func (d *Doctor) checkSecretResponse(ctx context.Context) DoctorCheck {
+	check := DoctorCheck{Name: "Secret response"}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.config.SecretURL, nil)
+	if err != nil {
+		check.Status, check.Detail = CheckFail, err.Error()
+		return check
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		check.Status, check.Detail = CheckFail, err.Error()
+		return check
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		check.Status, check.Detail = CheckFail, "secret endpoint returned "+resp.Status
+		return check
+	}
+	token, err := d.extractor.Extract(resp)
+	if err != nil {
+		check.Status, check.Detail = CheckFail, err.Error()
+		return check
+	}
+	check.Status, check.Detail = CheckPass, fmt.Sprintf("token received (%d characters)", len(token))
+	return check
+}
+
+// printDoctorChecks writes the checklist and a summary, returning the number of failures
+func printDoctorChecks(w io.Writer, checks []DoctorCheck) int {
+	counts := make(map[CheckStatus]int)
+	for _, check := range checks {
+		counts[check.Status]++
+		var marker string
+		switch check.Status {
+		case CheckPass:
+			marker = "✅ PASS"
+		case CheckWarn:
+			marker = "⚠️  WARN"
+		default:
+			marker = "❌ FAIL"
+		}
+		fmt.Fprintf(w, "%s  %-32s %s\n", marker, check.Name, check.Detail)
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", counts[CheckPass], counts[CheckWarn], counts[CheckFail])
+	return counts[CheckFail]
+}
+
+// runDoctorCommand checks connectivity to the configured endpoints
+func runDoctorCommand(configResult *ConfigResult) error {
+	if len(configResult.Args) > 0 {
+		printCommandHelp(os.Stderr, "doctor")
+		return fmt.Errorf("doctor takes no arguments")
+	}
+	if configResult.ConfigDiscovered {
+		fmt.Printf("🔧 Using configuration file: %s\n", configResult.ConfigFile)
+	}
+	if configResult.Profile != "" {
+		fmt.Printf("🔧 Using configuration profile: %s\n", configResult.Profile)
+	}
+
+	doctor, err := NewDoctor(configResult.Config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🩺 Checking connectivity to the configured endpoints...")
+	checks := doctor.Run(context.Background())
+	if failed := printDoctorChecks(os.Stdout, checks); failed > 0 {
+		return NewAppError(ErrCodeNetwork, fmt.Sprintf("%d of %d checks failed", failed, len(checks)), nil)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newDoctorTestServer starts a TLS server that rejects HEAD on the
+// application endpoint and returns a config trusting its certificate
+func newDoctorTestServer(t *testing.T) (*httptest.Server, *Config) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/secret":
+			w.Write([]byte(`{"result": "test-token"}`))
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0644); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.SecretURL = server.URL + "/secret"
+	config.ApplicationURL = server.URL + "/apply"
+	config.Timeout = 5
+	config.TLS.CAFile = caFile
+	return server, config
+}
+
+func doctorCheckStatuses(checks []DoctorCheck) map[string]CheckStatus {
+	statuses := make(map[string]CheckStatus)
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestDoctorRun(t *testing.T) {
+	server, config := newDoctorTestServer(t)
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	doctor, err := NewDoctor(config)
+	if err != nil {
+		t.Fatalf("Failed to create doctor: %v", err)
+	}
+	checks := doctor.Run(context.Background())
+
+	expected := []string{
+		"Proxy",
+		"DNS 127.0.0.1",
+		"TCP " + address,
+		"TLS " + address,
+		"HTTP secret endpoint",
+		"HTTP application endpoint",
+		"Clock skew",
+		"Secret response",
+	}
+	if len(checks) != len(expected) {
+		t.Fatalf("Expected %d checks, got %+v", len(expected), checks)
+	}
+	for i, check := range checks {
+		if check.Name != expected[i] {
+			t.Errorf("Expected check %d to be %q, got %q", i, expected[i], check.Name)
+		}
+		if check.Status != CheckPass {
+			t.Errorf("Expected %s to pass, got %s: %s", check.Name, check.Status, check.Detail)
+		}
+	}
+	if detail := checks[5].Detail; !strings.HasPrefix(detail, "OPTIONS 204") {
+		t.Errorf("Expected OPTIONS fallback for the application endpoint, got %q", detail)
+	}
+
+	var out bytes.Buffer
+	if failed := printDoctorChecks(&out, checks); failed != 0 {
+		t.Errorf("Expected no failures, got %d", failed)
+	}
+	if !strings.Contains(out.String(), "8 passed, 0 warnings, 0 failed") {
+		t.Errorf("Expected summary line, got %q", out.String())
+	}
+}
+
+func TestDoctorRunFailures(t *testing.T) {
+	server, config := newDoctorTestServer(t)
+	config.TLS.CAFile = ""
+	config.SecretResponse.Mode = "header"
+	config.SecretResponse.Header = "X-Token"
+
+	doctor, err := NewDoctor(config)
+	if err != nil {
+		t.Fatalf("Failed to create doctor: %v", err)
+	}
+	doctor.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+
+	statuses := doctorCheckStatuses(doctor.Run(context.Background()))
+	address := strings.TrimPrefix(server.URL, "https://")
+	if statuses["TLS "+address] != CheckFail {
+		t.Errorf("Expected untrusted certificate to fail, got %s", statuses["TLS "+address])
+	}
+	if statuses["HTTP secret endpoint"] != CheckFail {
+		t.Errorf("Expected HTTP check to fail, got %s", statuses["HTTP secret endpoint"])
+	}
+	if statuses["Clock skew"] != CheckWarn {
+		t.Errorf("Expected missing Date header to warn, got %s", statuses["Clock skew"])
+	}
+	if statuses["Secret response"] != CheckFail {
+		t.Errorf("Expected secret response to fail, got %s", statuses["Secret response"])
+	}
+
+	// With a trusted certificate, the only failures are the skew and the token header
+	_, config = newDoctorTestServer(t)
+	config.SecretResponse.Mode = "header"
+	config.SecretResponse.Header = "X-Token"
+	if doctor, err = NewDoctor(config); err != nil {
+		t.Fatalf("Failed to create doctor: %v", err)
+	}
+	doctor.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+
+	statuses = doctorCheckStatuses(doctor.Run(context.Background()))
+	if statuses["Clock skew"] != CheckFail {
+		t.Errorf("Expected 10 minute clock skew to fail, got %s", statuses["Clock skew"])
+	}
+	if statuses["Secret response"] != CheckFail {
+		t.Errorf("Expected missing token header to fail, got %s", statuses["Secret response"])
+	}
+}
+
+func TestDoctorCertificateExpiry(t *testing.T) {
+	server, config := newDoctorTestServer(t)
+	doctor, err := NewDoctor(config)
+	if err != nil {
+		t.Fatalf("Failed to create doctor: %v", err)
+	}
+	cert := server.Certificate()
+
+	doctor.now = func() time.Time { return cert.NotAfter.Add(-24 * time.Hour) }
+	if check := doctor.certificateCheck(DoctorCheck{}, 0, cert); check.Status != CheckWarn {
+		t.Errorf("Expected certificate expiring tomorrow to warn, got %s", check.Status)
+	}
+	doctor.now = func() time.Time { return cert.NotAfter.Add(time.Hour) }
+	if check := doctor.certificateCheck(DoctorCheck{}, 0, cert); check.Status != CheckFail {
+		t.Errorf("Expected expired certificate to fail, got %s", check.Status)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envBinding binds a MICV_* environment variable to a config field
+type envBinding struct {
+	name  string
+	field string
+	apply func(config *Config, value string) error
+}
+
+// configEnvBindings lists the environment equivalents of the configuration flags
+var configEnvBindings = []envBinding{
+	{"MICV_SECRET_URL", "secret_url", func(c *Config, v string) error { c.SecretURL = v; return nil }},
+	{"MICV_APPLICATION_URL", "application_url", func(c *Config, v string) error { c.ApplicationURL = v; return nil }},
+	{"MICV_TIMEOUT", "timeout_seconds", func(c *Config, v string) (err error) { c.Timeout, err = parsePositiveInt(v); return }},
+	{"MICV_PROXY_URL", "proxy_url", func(c *Config, v string) error { c.ProxyURL = v; return nil }},
+	{"MICV_CA_CERT", "tls.ca_file", func(c *Config, v string) error { c.TLS.CAFile = v; return nil }},
+	{"MICV_CLIENT_CERT", "tls.cert_file", func(c *Config, v string) error { c.TLS.CertFile = v; return nil }},
+	{"MICV_CLIENT_KEY", "tls.key_file", func(c *Config, v string) error { c.TLS.KeyFile = v; return nil }},
+	{"MICV_TLS_MIN_VERSION", "tls.min_version", func(c *Config, v string) error {
+		if _, err := parseTLSVersion(v); err != nil {
+			return err
+		}
+		c.TLS.MinVersion = v
+		return nil
+	}},
+	{"MICV_INSECURE_SKIP_VERIFY", "tls.insecure_skip_verify", func(c *Config, v string) (err error) {
+		c.TLS.InsecureSkipVerify, err = strconv.ParseBool(v)
+		return
+	}},
+	{"MICV_ALLOW_INSECURE", "allow_insecure", func(c *Config, v string) (err error) {
+		c.AllowInsecure, err = strconv.ParseBool(v)
+		return
+	}},
+	{"MICV_TOKEN_CACHE_TTL", "token_cache_ttl_seconds", func(c *Config, v string) (err error) {
+		c.TokenCacheTTL, err = parseNonNegativeInt(v)
+		return
+	}},
+	{"MICV_AUTH_SCHEME", "auth.scheme", func(c *Config, v string) error { c.Auth.Scheme = v; return nil }},
+	{"MICV_SECRET_MODE", "secret_response.mode", func(c *Config, v string) error { c.SecretResponse.Mode = v; return nil }},
+	{"MICV_SECRET_PATH", "secret_response.path", func(c *Config, v string) error { c.SecretResponse.Path = v; return nil }},
+	{"MICV_SECRET_HEADER", "secret_response.header", func(c *Config, v string) error { c.SecretResponse.Header = v; return nil }},
+	{"MICV_RATE_LIMIT", "rate_limits.*.requests_per_second", func(c *Config, v string) error {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 {
+			return fmt.Errorf("must be a non-negative number")
+		}
+		limit := defaultRateLimit(c)
+		limit.RequestsPerSecond = rate
+		c.RateLimits[defaultRateLimitHost] = limit
+		return nil
+	}},
+	{"MICV_RATE_BURST", "rate_limits.*.burst", func(c *Config, v string) error {
+		burst, err := parsePositiveInt(v)
+		if err != nil {
+			return err
+		}
+		limit := defaultRateLimit(c)
+		limit.Burst = burst
+		c.RateLimits[defaultRateLimitHost] = limit
+		return nil
+	}},
+	{"MICV_METRICS_FILE", "metrics.file", func(c *Config, v string) error { c.Metrics.File = v; return nil }},
+	{"MICV_METRICS_ADDR", "metrics.addr", func(c *Config, v string) error { c.Metrics.Addr = v; return nil }},
+	{"MICV_TRACE_FILE", "tracing.file", func(c *Config, v string) error { c.Tracing.File = v; return nil }},
+	{"MICV_OTLP_ENDPOINT", "tracing.otlp_endpoint", func(c *Config, v string) error { c.Tracing.OTLPEndpoint = v; return nil }},
+	{"MICV_FORCE", "", func(c *Config, v string) (err error) { c.Force, err = strconv.ParseBool(v); return }},
+}
+
+// loadFromEnvironment loads configuration from environment variables
+func loadFromEnvironment(config *Config) error {
+	for _, binding := range configEnvBindings {
+		value := strings.TrimSpace(os.Getenv(binding.name))
+		if value == "" {
+			continue
+		}
+		if err := binding.apply(config, value); err != nil {
+			return envError(binding.name, value, err)
+		}
+	}
+	return nil
+}
+
+// RunEnvironment holds the run-level settings taken from the environment
+type RunEnvironment struct {
+	ConfigFile string
+	DataFile   string
+	Verbose    bool
+	RecordDir  string
+	ReplayDir  string
+	Applicant  *ApplicationData
+}
+
+// loadRunEnvironment reads MICV_CONFIG, MICV_DATA, MICV_VERBOSE, MICV_RECORD,
+// MICV_REPLAY and the applicant variables MICV_NAME, MICV_EMAIL,
+// MICV_JOB_TITLE and MICV_FINAL_ATTEMPT
+func loadRunEnvironment() (*RunEnvironment, error) {
+	env := &RunEnvironment{
+		ConfigFile: strings.TrimSpace(os.Getenv("MICV_CONFIG")),
+		DataFile:   strings.TrimSpace(os.Getenv("MICV_DATA")),
+		RecordDir:  strings.TrimSpace(os.Getenv("MICV_RECORD")),
+		ReplayDir:  strings.TrimSpace(os.Getenv("MICV_REPLAY")),
+	}
+
+	if value := strings.TrimSpace(os.Getenv("MICV_VERBOSE")); value != "" {
+		verbose, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, envError("MICV_VERBOSE", value, err)
+		}
+		env.Verbose = verbose
+	}
+
+	applicant, err := applicantFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	env.Applicant = applicant
+
+	return env, nil
+}
+
+// applicantFromEnvironment builds application data from MICV_NAME, MICV_EMAIL,
+// MICV_JOB_TITLE and MICV_FINAL_ATTEMPT. It returns nil when none are set.
+func applicantFromEnvironment() (*ApplicationData, error) {
+	name := strings.TrimSpace(os.Getenv("MICV_NAME"))
+	email := strings.TrimSpace(os.Getenv("MICV_EMAIL"))
+	jobTitle := strings.TrimSpace(os.Getenv("MICV_JOB_TITLE"))
+	finalAttempt := strings.TrimSpace(os.Getenv("MICV_FINAL_ATTEMPT"))
+
+	if name == "" && email == "" && jobTitle == "" && finalAttempt == "" {
+		return nil, nil
+	}
+
+	appData := &ApplicationData{
+		Name:     name,
+		Email:    email,
+		JobTitle: jobTitle,
+	}
+
+	if finalAttempt != "" {
+		value, err := strconv.ParseBool(finalAttempt)
+		if err != nil {
+			return nil, envError("MICV_FINAL_ATTEMPT", finalAttempt, err)
+		}
+		if value {
+			appData.FinalAttempt = &value
+		}
+	}
+
+	if err := validateApplicationData(appData); err != nil {
+		return nil, NewAppError(ErrCodeConfig, "Incomplete applicant environment variables", err).
+			WithContext("variables", "MICV_NAME, MICV_EMAIL, MICV_JOB_TITLE")
+	}
+
+	return appData, nil
+}
+
+// defaultRateLimit returns the "*" rate limit, creating the map if needed
+func defaultRateLimit(config *Config) RateLimit {
+	if config.RateLimits == nil {
+		config.RateLimits = make(map[string]RateLimit)
+	}
+	limit, ok := config.RateLimits[defaultRateLimitHost]
+	if !ok {
+		limit.Burst = 1
+	}
+	return limit
+}
+
+// parsePositiveInt parses an integer greater than zero
+func parsePositiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("must be a positive integer")
+	}
+	return n, nil
+}
+
+// parseNonNegativeInt parses an integer of zero or more
+func parseNonNegativeInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("must be a non-negative integer")
+	}
+	return n, nil
+}
+
+// envError reports a malformed environment variable
+func envError(name, value string, err error) *AppError {
+	return NewAppError(ErrCodeConfig, "Invalid environment variable", fmt.Errorf("%s=%q: %w", name, value, err)).
+		WithContext("variable", name)
+}
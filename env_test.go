@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFromEnvironment(t *testing.T) {
+	t.Setenv("MICV_TIMEOUT", "45")
+	t.Setenv("MICV_CA_CERT", "/etc/micv/ca.pem")
+	t.Setenv("MICV_TLS_MIN_VERSION", "1.3")
+	t.Setenv("MICV_INSECURE_SKIP_VERIFY", "true")
+	t.Setenv("MICV_AUTH_SCHEME", "bearer")
+	t.Setenv("MICV_RATE_LIMIT", "0.5")
+	t.Setenv("MICV_FORCE", "1")
+
+	config := DefaultConfig()
+	if err := loadFromEnvironment(config); err != nil {
+		t.Fatalf("loadFromEnvironment failed: %v", err)
+	}
+
+	if config.Timeout != 45 {
+		t.Errorf("Expected Timeout 45, got %d", config.Timeout)
+	}
+	if config.TLS.CAFile != "/etc/micv/ca.pem" || config.TLS.MinVersion != "1.3" || !config.TLS.InsecureSkipVerify {
+		t.Errorf("Expected TLS settings from environment, got %+v", config.TLS)
+	}
+	if config.Auth.Scheme != "bearer" {
+		t.Errorf("Expected auth scheme bearer, got %s", config.Auth.Scheme)
+	}
+	if limit := config.RateLimits[defaultRateLimitHost]; limit.RequestsPerSecond != 0.5 || limit.Burst != 1 {
+		t.Errorf("Expected default rate limit from environment, got %+v", limit)
+	}
+	if !config.Force {
+		t.Error("Expected Force from environment")
+	}
+}
+
+func TestLoadFromEnvironmentInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"MICV_TIMEOUT", "thirty"},
+		{"MICV_TIMEOUT", "-5"},
+		{"MICV_INSECURE_SKIP_VERIFY", "maybe"},
+		{"MICV_TOKEN_CACHE_TTL", "-1"},
+		{"MICV_TLS_MIN_VERSION", "2.0"},
+		{"MICV_RATE_LIMIT", "fast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+
+			err := loadFromEnvironment(DefaultConfig())
+			var appErr *AppError
+			if !errors.As(err, &appErr) || appErr.Code != ErrCodeConfig {
+				t.Fatalf("Expected %s error, got %v", ErrCodeConfig, err)
+			}
+			if appErr.Context["variable"] != tt.name {
+				t.Errorf("Expected variable %s in error context, got %v", tt.name, appErr.Context["variable"])
+			}
+		})
+	}
+}
+
+func TestApplicantFromEnvironment(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MICV_NAME", "John Doe")
+	t.Setenv("MICV_EMAIL", "john@example.com")
+	t.Setenv("MICV_JOB_TITLE", "Software Engineer")
+	t.Setenv("MICV_FINAL_ATTEMPT", "true")
+	t.Setenv("MICV_VERBOSE", "true")
+
+	configResult := loadConfigWithArgs(t)
+	if !configResult.Verbose {
+		t.Error("Expected verbose mode from MICV_VERBOSE")
+	}
+	applicant := configResult.Applicant
+	if applicant == nil {
+		t.Fatal("Expected applicant from environment")
+	}
+	if applicant.Name != "John Doe" || applicant.Email != "john@example.com" || applicant.JobTitle != "Software Engineer" {
+		t.Errorf("Unexpected applicant %+v", applicant)
+	}
+	if applicant.FinalAttempt == nil || !*applicant.FinalAttempt {
+		t.Error("Expected final attempt from MICV_FINAL_ATTEMPT")
+	}
+
+	// Positional arguments take precedence over the environment
+	configResult = loadConfigWithArgs(t, "Jane Doe", "jane@example.com", "Engineer")
+	if configResult.Applicant != nil {
+		t.Error("Expected positional arguments to take precedence over applicant variables")
+	}
+}
+
+func TestApplicantFromEnvironmentInvalid(t *testing.T) {
+	t.Setenv("MICV_NAME", "John Doe")
+	if _, err := applicantFromEnvironment(); err == nil {
+		t.Error("Expected error for incomplete applicant variables")
+	}
+
+	t.Setenv("MICV_EMAIL", "john@example.com")
+	t.Setenv("MICV_JOB_TITLE", "Software Engineer")
+	t.Setenv("MICV_FINAL_ATTEMPT", "sure")
+	if _, err := applicantFromEnvironment(); err == nil {
+		t.Error("Expected error for malformed MICV_FINAL_ATTEMPT")
+	}
+}
+
+// runEnvFlags maps the flags that are not in configFlagFields to their
+// environment variables
+var runEnvFlags = map[string]string{
+	"config":  "MICV_CONFIG",
+	"profile": "MICV_PROFILE",
+	"data":    "MICV_DATA",
+	"verbose": "MICV_VERBOSE",
+	"record":  "MICV_RECORD",
+	"replay":  "MICV_REPLAY",
+	"force":   "MICV_FORCE",
+}
+
+// flagsWithoutEnv are the flags that have no environment equivalent
+var flagsWithoutEnv = map[string]string{
+	"help":                 "shows help",
+	"version":              "deprecated mode flag",
+	"generate-data-json":   "deprecated mode flag",
+	"generate-config-json": "deprecated mode flag",
+	"flags-over-env":       "decides between flags and the environment",
+	"var":                  "repeatable template variables",
+}
+
+func TestEveryFlagHasEnvironmentBinding(t *testing.T) {
+	fs := flag.NewFlagSet("micv", flag.ContinueOnError)
+	if _, err := loadConfigFromArgs(fs, []string{"version"}); err != nil {
+		t.Fatalf("loadConfigFromArgs failed: %v", err)
+	}
+
+	bound := make(map[string]bool)
+	for _, binding := range configEnvBindings {
+		bound[binding.field] = true
+	}
+
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := flagsWithoutEnv[f.Name]; ok {
+			return
+		}
+		if field, ok := configFlagFields[f.Name]; ok && bound[field] {
+			return
+		}
+		if _, ok := runEnvFlags[f.Name]; ok {
+			return
+		}
+		t.Errorf("Flag --%s has no MICV_* environment equivalent", f.Name)
+	})
+}
+
+func TestConfigFileFromEnvironment(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	configFile := filepath.Join(t.TempDir(), "prod.json")
+	if err := os.WriteFile(configFile, []byte(`{"timeout_seconds": 90}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("MICV_CONFIG", configFile)
+
+	configResult := loadConfigWithArgs(t, "Jane Doe", "jane@example.com", "Engineer")
+	if configResult.ConfigFile != configFile || configResult.ConfigDiscovered {
+		t.Errorf("Expected config file %s from MICV_CONFIG, got %s", configFile, configResult.ConfigFile)
+	}
+	if configResult.Config.Timeout != 90 {
+		t.Errorf("Expected timeout from the MICV_CONFIG file, got %d", configResult.Config.Timeout)
+	}
+
+	// --config takes precedence over MICV_CONFIG
+	otherFile := filepath.Join(t.TempDir(), "other.json")
+	if err := os.WriteFile(otherFile, []byte(`{"timeout_seconds": 15}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	configResult = loadConfigWithArgs(t, "--config", otherFile, "Jane Doe", "jane@example.com", "Engineer")
+	if configResult.Config.Timeout != 15 {
+		t.Errorf("Expected --config to take precedence, got timeout %d", configResult.Config.Timeout)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileReferencePrefix marks a string value that is replaced by a file's contents
+const fileReferencePrefix = "file:"
+
+// escapedFileReferencePrefix starts a value that is kept as a literal "file:..."
+const escapedFileReferencePrefix = "$" + fileReferencePrefix
+
+// expandReferences expands ${VAR} and ${VAR:-default} in every string value
+// of a JSON document and replaces "file:<path>" values with the contents of
+// that file. Relative paths are resolved against baseDir. "$${" and a leading
+// "$file:" escape a literal "${" and "file:".
+func expandReferences(data []byte, baseDir string) ([]byte, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	expanded, err := expandValue(document, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(expanded)
+}
+
+// expandValue expands the string values below value
+func expandValue(value interface{}, baseDir string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			expanded, err := expandValue(child, baseDir)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case []interface{}:
+		for i, child := range v {
+			expanded, err := expandValue(child, baseDir)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	case string:
+		return expandString(v, baseDir)
+	default:
+		return value, nil
+	}
+}
+
+// expandString expands variables in s and then resolves a file reference
+func expandString(s, baseDir string) (string, error) {
+	expanded, err := expandVariables(s)
+	if err != nil {
+		return "", err
+	}
+
+	if literal, ok := strings.CutPrefix(expanded, escapedFileReferencePrefix); ok {
+		return fileReferencePrefix + literal, nil
+	}
+
+	path, ok := strings.CutPrefix(expanded, fileReferencePrefix)
+	if !ok {
+		return expanded, nil
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file reference: %w", err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// expandVariables replaces ${VAR} and ${VAR:-default}; $${ produces a literal ${
+func expandVariables(s string) (string, error) {
+	var b strings.Builder
+
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+
+		if start > 0 && s[start-1] == '$' {
+			b.WriteString(s[:start-1])
+			b.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference in %q", s)
+		}
+		end += start
+
+		b.WriteString(s[:start])
+		value, err := lookupVariable(s[start+2 : end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+		s = s[end+1:]
+	}
+}
+
+// lookupVariable resolves "VAR" or "VAR:-default" from the environment
+func lookupVariable(expr string) (string, error) {
+	name, fallback, hasDefault := strings.Cut(expr, ":-")
+	if name == "" {
+		return "", fmt.Errorf("empty variable name in ${%s}", expr)
+	}
+
+	if value := os.Getenv(name); value != "" {
+		return value, nil
+	}
+	if hasDefault {
+		return fallback, nil
+	}
+	return "", fmt.Errorf("environment variable %s is not set", name)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandVariables(t *testing.T) {
+	t.Setenv("MICV_TEST_PORTAL", "https://portal.test.com")
+	t.Setenv("MICV_TEST_EMPTY", "")
+
+	tests := []struct {
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{"${MICV_TEST_PORTAL}/apply", "https://portal.test.com/apply", false},
+		{"${MICV_TEST_UNSET:-https://default.test.com}/apply", "https://default.test.com/apply", false},
+		{"${MICV_TEST_EMPTY:-fallback}", "fallback", false},
+		{"cost: $${MICV_TEST_PORTAL}", "cost: ${MICV_TEST_PORTAL}", false},
+		{"no variables", "no variables", false},
+		{"${MICV_TEST_UNSET}", "", true},
+		{"${MICV_TEST_PORTAL", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := expandVariables(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadApplicationDataExpansion(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("MICV_TEST_TITLE", "Software Engineer")
+
+	if err := os.WriteFile(filepath.Join(dir, "why.md"), []byte("Because I ship.\n\nReliably.\n"), 0644); err != nil {
+		t.Fatalf("Failed to write referenced file: %v", err)
+	}
+
+	dataFile := filepath.Join(dir, "data.json")
+	content := `{
+  "name": "John Doe",
+  "email": "${MICV_TEST_EMAIL:-john@example.com}",
+  "job_title": "${MICV_TEST_TITLE}",
+  "extra_information": {
+    "why_hire_me": "file:why.md"
+  }
+}`
+	if err := os.WriteFile(dataFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	appData, err := LoadApplicationData(dataFile)
+	if err != nil {
+		t.Fatalf("LoadApplicationData failed: %v", err)
+	}
+	if appData.Email != "john@example.com" || appData.JobTitle != "Software Engineer" {
+		t.Errorf("Expected expanded fields, got %+v", appData)
+	}
+	extra, _ := appData.ExtraInformation.(map[string]interface{})
+	if why := extra["why_hire_me"]; why != "Because I ship.\n\nReliably." {
+		t.Errorf("Expected contents of referenced file, got %q", why)
+	}
+
+	missing := filepath.Join(dir, "missing.json")
+	if err := os.WriteFile(missing, []byte(`{"name": "file:nowhere.md"}`), 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	if _, err := LoadApplicationData(missing); err == nil || !strings.Contains(err.Error(), "name") {
+		t.Errorf("Expected error naming the field with a missing file reference, got %v", err)
+	}
+}
+
+func TestLoadApplicationDataEscapes(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "data.json")
+	content := `{
+  "name": "John Doe",
+  "email": "john@example.com",
+  "job_title": "Software Engineer",
+  "extra_information": {
+    "why_hire_me": "I template scripts with $${HOME} daily",
+    "portfolio": "$file:///home/john/portfolio.html",
+    "note": "costs $5, not file:x"
+  }
+}`
+	if err := os.WriteFile(dataFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	appData, err := LoadApplicationData(dataFile)
+	if err != nil {
+		t.Fatalf("LoadApplicationData failed: %v", err)
+	}
+	extra, _ := appData.ExtraInformation.(map[string]interface{})
+	expected := map[string]string{
+		"why_hire_me": "I template scripts with ${HOME} daily",
+		"portfolio":   "file:///home/john/portfolio.html",
+		"note":        "costs $5, not file:x",
+	}
+	for key, want := range expected {
+		if extra[key] != want {
+			t.Errorf("Expected %s %q, got %q", key, want, extra[key])
+		}
+	}
+}
+
+func TestLoadConfigExpansion(t *testing.T) {
+	t.Setenv("MICV_TEST_PORTAL", "https://portal.test.com")
+
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	content := `{"secret_url": "${MICV_TEST_PORTAL}/secret", "application_url": "${MICV_TEST_PORTAL}/apply"}`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	config := DefaultConfig()
+	if err := loadConfigFromFile(configFile, config); err != nil {
+		t.Fatalf("loadConfigFromFile failed: %v", err)
+	}
+	if config.SecretURL != "https://portal.test.com/secret" || config.ApplicationURL != "https://portal.test.com/apply" {
+		t.Errorf("Expected expanded URLs, got %s and %s", config.SecretURL, config.ApplicationURL)
+	}
+}
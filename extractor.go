@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"micv/client"
+)
+
+// Secret response extraction modes
+const (
+	SecretModeJSON   = "json"
+	SecretModeText   = "text"
+	SecretModeHeader = "header"
+)
+
+// SecretResponseConfig describes where the token lives in the secret endpoint response
+type SecretResponseConfig struct {
+	Mode   string `json:"mode,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Header string `json:"header,omitempty"`
+}
+
+// Secret extractors from the client package
+type (
+	SecretExtractor       = client.SecretExtractor
+	JSONSecretExtractor   = client.JSONSecretExtractor
+	TextSecretExtractor   = client.TextSecretExtractor
+	HeaderSecretExtractor = client.HeaderSecretExtractor
+)
+
+// NewSecretExtractor creates the extractor described by the secret response configuration
+func NewSecretExtractor(config SecretResponseConfig) (SecretExtractor, error) {
+	switch strings.ToLower(config.Mode) {
+	case "", SecretModeJSON:
+		return JSONSecretExtractor{Path: config.Path}, nil
+	case SecretModeText:
+		return TextSecretExtractor{}, nil
+	case SecretModeHeader:
+		if config.Header == "" {
+			return nil, fmt.Errorf("header name is required for header secret mode")
+		}
+		return HeaderSecretExtractor{Header: config.Header}, nil
+	default:
+		return nil, fmt.Errorf("unsupported secret response mode: %s", config.Mode)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSecretExtractors(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        SecretResponseConfig
+		body          string
+		header        http.Header
+		expectedToken string
+		errorContains string
+	}{
+		{
+			name:          "default JSON result field",
+			config:        SecretResponseConfig{},
+			body:          `{"result":"token123"}`,
+			expectedToken: "token123",
+		},
+		{
+			name:          "dotted JSON path",
+			config:        SecretResponseConfig{Mode: "json", Path: "data.auth.token"},
+			body:          `{"data":{"auth":{"token":"nested"}}}`,
+			expectedToken: "nested",
+		},
+		{
+			name:          "JSON pointer with array index",
+			config:        SecretResponseConfig{Path: "/tokens/1/value"},
+			body:          `{"tokens":[{"value":"first"},{"value":"second"}]}`,
+			expectedToken: "second",
+		},
+		{
+			name:          "numeric JSON value",
+			config:        SecretResponseConfig{Path: "code"},
+			body:          `{"code":12345}`,
+			expectedToken: "12345",
+		},
+		{
+			name:          "missing JSON path",
+			config:        SecretResponseConfig{Path: "data.token"},
+			body:          `{"data":{}}`,
+			errorContains: "empty result",
+		},
+		{
+			name:          "non-scalar JSON value",
+			config:        SecretResponseConfig{Path: "data"},
+			body:          `{"data":{"token":"x"}}`,
+			errorContains: "not a scalar",
+		},
+		{
+			name:          "plain text body",
+			config:        SecretResponseConfig{Mode: "text"},
+			body:          "plain-token\n",
+			expectedToken: "plain-token",
+		},
+		{
+			name:          "empty plain text body",
+			config:        SecretResponseConfig{Mode: "text"},
+			body:          "  \n",
+			errorContains: "empty result",
+		},
+		{
+			name:          "response header",
+			config:        SecretResponseConfig{Mode: "header", Header: "X-Secret"},
+			header:        http.Header{"X-Secret": []string{"header-token"}},
+			expectedToken: "header-token",
+		},
+		{
+			name:          "missing response header",
+			config:        SecretResponseConfig{Mode: "header", Header: "X-Secret"},
+			errorContains: "empty X-Secret header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor, err := NewSecretExtractor(tt.config)
+			if err != nil {
+				t.Fatalf("NewSecretExtractor failed: %v", err)
+			}
+
+			resp := createResponse(200, tt.body)
+			for key, values := range tt.header {
+				resp.Header[key] = values
+			}
+
+			token, err := extractor.Extract(resp)
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error containing %q, got: %v", tt.errorContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if token != tt.expectedToken {
+				t.Errorf("Expected token %q, got %q", tt.expectedToken, token)
+			}
+		})
+	}
+}
+
+func TestNewSecretExtractorErrors(t *testing.T) {
+	if _, err := NewSecretExtractor(SecretResponseConfig{Mode: "xml"}); err == nil {
+		t.Error("Expected error for unsupported mode")
+	}
+	if _, err := NewSecretExtractor(SecretResponseConfig{Mode: "header"}); err == nil {
+		t.Error("Expected error for header mode without header name")
+	}
+}
+
+func TestGetAuthTokenWithExtractor(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		GetFunc: func(url string) (*http.Response, error) {
+			return createResponse(200, "Bearer plain-text-token"), nil
+		},
+	}
+
+	token, err := getAuthTokenWithExtractor(mockClient, "https://example.com/secret", TextSecretExtractor{})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if token != "Bearer plain-text-token" {
+		t.Errorf("Expected token 'Bearer plain-text-token', got %q", token)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"micv/client"
+)
+
+// defaultHookTimeout bounds each hook command when no timeout is configured
+const defaultHookTimeout = 30 * time.Second
+
+// HookConfig configures shell commands that run around each submission
+type HookConfig struct {
+	PreSubmit      string `json:"pre_submit,omitempty"`
+	PostSubmit     string `json:"post_submit,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+// configured reports whether any hook command is set
+func (h HookConfig) configured() bool {
+	return h.PreSubmit != "" || h.PostSubmit != ""
+}
+
+// timeout returns the time limit for a single hook command
+func (h HookConfig) timeout() time.Duration {
+	if h.TimeoutSeconds > 0 {
+		return time.Duration(h.TimeoutSeconds) * time.Second
+	}
+	return defaultHookTimeout
+}
+
+// hookResult is the JSON document passed to the post-submit hook on stdin
+type hookResult struct {
+	ApplicationURL string          `json:"application_url"`
+	IdempotencyKey string          `json:"idempotency_key,omitempty"`
+	Succeeded      bool            `json:"succeeded"`
+	StatusCode     int             `json:"status_code,omitempty"`
+	Status         string          `json:"status,omitempty"`
+	Body           string          `json:"body,omitempty"`
+	DurationMS     int64           `json:"duration_ms"`
+	Error          string          `json:"error,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+// CommandHook runs the configured hook commands through the shell
+type CommandHook struct {
+	config HookConfig
+	logger *Logger
+}
+
+// NewCommandHook creates a hook for the configured commands
+func NewCommandHook(config HookConfig, logger *Logger) *CommandHook {
+	return &CommandHook{config: config, logger: logger}
+}
+
+// BeforeSubmit runs the pre-submit command with the payload on stdin; a
+// non-zero exit vetoes the submission
+func (h *CommandHook) BeforeSubmit(ctx context.Context, payload []byte) error {
+	if h.config.PreSubmit == "" {
+		return nil
+	}
+
+	fmt.Printf("🪝 Running pre-submit hook: %s\n", h.config.PreSubmit)
+	if err := h.run(ctx, h.config.PreSubmit, "pre_submit", payload); err != nil {
+		return fmt.Errorf("pre-submit hook %q: %w", h.config.PreSubmit, err)
+	}
+	return nil
+}
+
+// AfterSubmit runs the post-submit command with the result on stdin; a
+// failure is reported but does not change the outcome of the submission
+func (h *CommandHook) AfterSubmit(ctx context.Context, result client.SubmitResult) {
+	if h.config.PostSubmit == "" {
+		return
+	}
+
+	doc := hookResult{
+		ApplicationURL: result.ApplicationURL,
+		IdempotencyKey: result.IdempotencyKey,
+		Succeeded:      result.Err == nil && result.Response.Succeeded(),
+		DurationMS:     result.Duration.Milliseconds(),
+		Payload:        result.Payload,
+	}
+	if result.Response != nil {
+		doc.StatusCode = result.Response.StatusCode
+		doc.Status = result.Response.Status
+		doc.Body = string(result.Response.Body)
+	}
+	if result.Err != nil {
+		doc.Error = result.Err.Error()
+	}
+	input, err := json.Marshal(doc)
+	if err != nil {
+		h.logger.Warn("Failed to encode post-submit hook input", "error", err)
+		return
+	}
+
+	// Run even if the submission used up its deadline
+	fmt.Printf("🪝 Running post-submit hook: %s\n", h.config.PostSubmit)
+	if err := h.run(context.WithoutCancel(ctx), h.config.PostSubmit, "post_submit", input); err != nil {
+		h.logger.Warn("Post-submit hook failed", "command", h.config.PostSubmit, "error", err)
+		fmt.Printf("⚠️  Post-submit hook failed: %v\n", err)
+	}
+}
+
+// run executes command with input on stdin, passing its output through and
+// including its stderr in the returned error
+func (h *CommandHook) run(ctx context.Context, command, event string, input []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, h.config.timeout())
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = os.Stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	cmd.Env = append(os.Environ(), "MICV_HOOK="+event)
+	// Don't wait for children of a killed shell that still hold the output pipes
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", h.config.timeout())
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+		return err
+	}
+	return nil
+}
+
+// shellCommand runs command through the platform shell
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"micv/client"
+)
+
+func TestCommandHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	preFile := filepath.Join(dir, "pre.json")
+	postFile := filepath.Join(dir, "post.json")
+	hook := NewCommandHook(HookConfig{
+		PreSubmit:  "cat > " + preFile + ` && test "$MICV_HOOK" = pre_submit`,
+		PostSubmit: "cat > " + postFile,
+	}, NewLogger(LogLevelError))
+
+	payload := []byte(`{"name": "John Doe"}`)
+	if err := hook.BeforeSubmit(context.Background(), payload); err != nil {
+		t.Fatalf("Expected pre-submit hook to pass, got %v", err)
+	}
+	if data, _ := os.ReadFile(preFile); string(data) != string(payload) {
+		t.Errorf("Expected payload on stdin, got %q", data)
+	}
+
+	hook.AfterSubmit(context.Background(), client.SubmitResult{
+		ApplicationURL: "https://example.com/apply",
+		Payload:        payload,
+		Response:       &SubmitResponse{StatusCode: 201, Status: "201 Created", Body: []byte("ok")},
+		Duration:       1500 * time.Millisecond,
+	})
+	data, err := os.ReadFile(postFile)
+	if err != nil {
+		t.Fatalf("Expected post-submit hook to run: %v", err)
+	}
+	var result hookResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Invalid post-submit input %q: %v", data, err)
+	}
+	if !result.Succeeded || result.StatusCode != 201 || result.Body != "ok" || result.DurationMS != 1500 {
+		t.Errorf("Unexpected post-submit input %+v", result)
+	}
+}
+
+func TestCommandHookVeto(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use a POSIX shell")
+	}
+
+	hook := NewCommandHook(HookConfig{PreSubmit: "echo 'job title not allowed' >&2; exit 3"}, NewLogger(LogLevelError))
+	err := hook.BeforeSubmit(context.Background(), []byte("{}"))
+	if err == nil || !strings.Contains(err.Error(), "job title not allowed") {
+		t.Errorf("Expected veto with hook stderr, got %v", err)
+	}
+
+	hook = NewCommandHook(HookConfig{PreSubmit: "sleep 5", TimeoutSeconds: 1}, NewLogger(LogLevelError))
+	err = hook.BeforeSubmit(context.Background(), []byte("{}"))
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected hook timeout, got %v", err)
+	}
+}
+
+func TestSubmitApplicationVetoedByHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use a POSIX shell")
+	}
+
+	config := DefaultConfig()
+	config.Hooks.PreSubmit = "exit 1"
+	config.SubmissionStoreFile = filepath.Join(t.TempDir(), "submissions.json")
+	config.TokenCacheTTL = 0
+	config.Auth = AuthConfig{Scheme: AuthSchemeStatic, TokenEnv: "MICV_TEST_HOOK_TOKEN"}
+	t.Setenv("MICV_TEST_HOOK_TOKEN", "token")
+
+	deps, err := NewAppDependencies(config, LogLevelError)
+	if err != nil {
+		t.Fatalf("Failed to create dependencies: %v", err)
+	}
+	service := NewApplicationService(deps)
+	err = service.SubmitApplication(context.Background(), createDefaultApplicationData("John Doe", "john@example.com", "Software Engineer", nil))
+	if !errors.Is(err, client.ErrSubmissionVetoed) {
+		t.Errorf("Expected vetoed submission, got %v", err)
+	}
+	if _, statErr := os.Stat(config.SubmissionStoreFile); !os.IsNotExist(statErr) {
+		t.Errorf("Expected vetoed submission not to be recorded, got %v", statErr)
+	}
+}
+
+func TestSubmitApplicationRunsHooksOnceWhenCachedTokenRejected(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use a POSIX shell")
+	}
+
+	logFile := filepath.Join(t.TempDir(), "hooks.log")
+	deps := NewMockDependencies()
+	deps.config.Hooks = HookConfig{
+		PreSubmit:  "echo pre >> " + logFile,
+		PostSubmit: "echo post >> " + logFile,
+	}
+	deps.tokenCache = NewTokenCache(filepath.Join(t.TempDir(), "tokens.json"), time.Hour)
+	deps.tokenCache.Put(deps.config.SecretURL, "stale-token")
+
+	submits := 0
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"fresh-token"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		submits++
+		if req.Header.Get("Authorization") != "fresh-token" {
+			return createResponse(401, `{"error":"unauthorized"}`), nil
+		}
+		return createResponse(200, `{"status":"success"}`), nil
+	}
+
+	err := NewApplicationService(deps).SubmitApplication(context.Background(), ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if submits != 2 {
+		t.Errorf("Expected the stale and fresh token to be sent, got %d submissions", submits)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Expected hooks to run: %v", err)
+	}
+	if runs := strings.Fields(string(data)); strings.Join(runs, ",") != "pre,post" {
+		t.Errorf("Expected each hook to run once, got %v", runs)
+	}
+}
@@ -1,214 +1,96 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"time"
+
+	"micv/client"
 )
 
 func main() {
-	// Load configuration
+	// Load configuration and resolve the command
 	configResult, err := LoadConfig()
 	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
 		fmt.Printf("❌ Error loading configuration: %v\n", err)
 		os.Exit(1)
 	}
-	config := configResult.Config
-
-	// Determine log level based on verbose flag
-	logLevel := LogLevelInfo
-	if configResult.Verbose {
-		logLevel = LogLevelDebug
-	}
-
-	// Initialize dependencies
-	deps := NewAppDependencies(config, logLevel)
-	logger := deps.Logger()
-
-	// Create application instance
-	app := NewApplication(deps)
-
-	// Load application data
-	appData, err := loadApplicationData(configResult)
-	if err != nil {
-		logger.Error("Failed to load application data", "error", err)
-		fmt.Printf("❌ Error loading application data: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(config.Timeout+10)*time.Second)
-	defer cancel()
-
-	// Run application
-	if err := app.Run(ctx, appData); err != nil {
-		logger.Error("Application execution failed", "error", err)
 
-		// Enhanced error reporting for users
-		if appErr, ok := err.(*AppError); ok {
-			fmt.Printf("❌ %s: %s\n", appErr.Code, appErr.Message)
-			if appErr.Cause != nil {
-				fmt.Printf("   Cause: %v\n", appErr.Cause)
-			}
-			for key, value := range appErr.Context {
-				fmt.Printf("   %s: %v\n", key, value)
-			}
-		} else {
-			fmt.Printf("❌ Error: %v\n", err)
+	if err := runCommand(configResult); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
 		}
+		printCommandError(err)
 		os.Exit(1)
 	}
 }
 
-// getAuthTokenWithClient fetches auth token using the provided HTTP client (testable version)
-func getAuthTokenWithClient(client HTTPClient, secretURL string) (string, error) {
-	// Make request to secret endpoint
-	resp, err := client.Get(secretURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Validate response status
-	if err := validateSecretResponse(resp); err != nil {
-		return "", err
-	}
-
-	// Read and parse response
-	return parseSecretResponse(resp)
-}
-
-// validateSecretResponse validates the HTTP response from secret endpoint
-func validateSecretResponse(resp *http.Response) error {
-	fmt.Printf("🌐 Secret endpoint HTTP Status: %d %s\n", resp.StatusCode, resp.Status)
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("secret endpoint returned non-success status: %d %s", resp.StatusCode, resp.Status)
-	}
-
-	return nil
-}
-
-// parseSecretResponse parses the secret endpoint response to extract token
-func parseSecretResponse(resp *http.Response) (string, error) {
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	fmt.Printf("📄 Secret endpoint response body: %s\n", string(body))
-
-	// Parse JSON response
-	var secretResp SecretResponse
-	if err := json.Unmarshal(body, &secretResp); err != nil {
-		return "", fmt.Errorf("failed to parse JSON response: %w", err)
-	}
-
-	if secretResp.Result == "" {
-		return "", fmt.Errorf("empty result in secret response")
-	}
-
-	return secretResp.Result, nil
-}
-
-// submitApplicationWithClient submits application using the provided HTTP client (testable version)
-func submitApplicationWithClient(client HTTPClient, applicationURL string, token string, appData ApplicationData) error {
-	// Prepare JSON data
-	jsonData, err := prepareApplicationJSON(appData)
-	if err != nil {
-		return err
-	}
-
-	// Create and send request
-	req, err := createApplicationRequest(applicationURL, token, jsonData)
-	if err != nil {
-		return err
-	}
-
-	// Execute request and handle response
-	return executeApplicationRequest(client, req)
-}
-
-// prepareApplicationJSON converts application data to JSON
-func prepareApplicationJSON(appData ApplicationData) ([]byte, error) {
-	jsonData, err := json.MarshalIndent(appData, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+// printCommandError reports a failed command, with the context of application errors
+func printCommandError(err error) {
+	// Enhanced error reporting for users
+	if appErr, ok := err.(*AppError); ok {
+		fmt.Printf("❌ %s: %s\n", appErr.Code, appErr.Message)
+		if appErr.Cause != nil {
+			fmt.Printf("   Cause: %v\n", appErr.Cause)
+		}
+		for key, value := range appErr.Context {
+			fmt.Printf("   %s: %v\n", key, value)
+		}
+		return
 	}
-
-	fmt.Printf("📋 Application data being sent:\n%s\n", string(jsonData))
-	return jsonData, nil
+	fmt.Printf("❌ Error: %v\n", err)
 }
 
-// createApplicationRequest creates HTTP request for application submission
-func createApplicationRequest(applicationURL string, token string, jsonData []byte) (*http.Request, error) {
-	req, err := http.NewRequest("POST", applicationURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", token)
-
-	return req, nil
+// getAuthTokenWithClient fetches auth token using the provided HTTP client (testable version)
+func getAuthTokenWithClient(httpClient HTTPClient, secretURL string) (string, error) {
+	return getAuthTokenWithExtractor(httpClient, secretURL, JSONSecretExtractor{})
 }
 
-// executeApplicationRequest executes the application request and handles response
-func executeApplicationRequest(client HTTPClient, req *http.Request) error {
-	// Make request
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read and process response
-	return processApplicationResponse(resp)
+// getAuthTokenWithExtractor makes a single token request and extracts the token with the given extractor
+func getAuthTokenWithExtractor(httpClient HTTPClient, secretURL string, extractor SecretExtractor) (string, error) {
+	c := client.New(secretURL, "",
+		client.WithHTTPClient(httpClient),
+		client.WithSecretExtractor(extractor),
+		client.WithOutput(os.Stdout),
+	)
+	return c.FetchTokenOnce()
 }
 
-// processApplicationResponse processes the application submission response
-func processApplicationResponse(resp *http.Response) error {
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
+// SubmitResponse holds the response to an application request
+type SubmitResponse = client.SubmitResponse
 
-	// Print results
-	fmt.Printf("🎯 Application submission HTTP Status: %d %s\n", resp.StatusCode, resp.Status)
-	fmt.Printf("📄 Application submission response body: %s\n", string(body))
+// ErrTokenRejected is returned when the application endpoint rejects the authorization token
+var ErrTokenRejected = client.ErrTokenRejected
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		fmt.Println("✅ Application submitted successfully!")
-	} else {
-		fmt.Println("⚠️  Application submission completed with non-success status")
-	}
-
-	return nil
+// submitApplicationWithClient makes a single application request using the provided HTTP client (testable version)
+func submitApplicationWithClient(httpClient HTTPClient, applicationURL string, token string, appData ApplicationData) error {
+	c := client.New("", applicationURL,
+		client.WithHTTPClient(httpClient),
+		client.WithOutput(os.Stdout),
+	)
+	_, err := c.SubmitOnce(context.Background(), token, appData)
+	return err
 }
 
 // loadApplicationData loads application data from file or command line arguments
 func loadApplicationData(configResult *ConfigResult) (ApplicationData, error) {
 	var appData ApplicationData
 
-	// Check remaining command line arguments (after flags)
-	args := flag.Args()
+	// Remaining command line arguments (after flags and the command name)
+	args := configResult.Args
 
 	// Validate that both --data flag and command line arguments are not provided together
 	if configResult.DataFile != "" && len(args) > 0 {
-		fmt.Println("❌ Error: Cannot use both --data flag and command line arguments together")
 		fmt.Println("💡 Please use either:")
 		fmt.Println("   - The --data flag to specify a JSON file: --data applicant-data.json")
 		fmt.Println("   - Command line arguments: \"Name\" \"email@example.com\" \"Job Title\"")
 		fmt.Println("   - Use --help for more information")
-		os.Exit(1)
+		return appData, fmt.Errorf("cannot use both --data flag and command line arguments together")
 	}
 
 	if configResult.DataFile != "" {
@@ -220,6 +102,10 @@ func loadApplicationData(configResult *ConfigResult) (ApplicationData, error) {
 		}
 		appData = *loadedData
 		fmt.Println("✅ Application data loaded successfully from file")
+	} else if len(args) == 0 && configResult.Applicant != nil {
+		// Use the applicant given through MICV_NAME, MICV_EMAIL and MICV_JOB_TITLE
+		fmt.Println("📖 Using application data from environment variables")
+		appData = *configResult.Applicant
 	} else {
 		if len(args) < 3 {
 			return appData, fmt.Errorf("insufficient arguments provided")
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"micv/client"
+	"micv/metrics"
+)
+
+// Operation label of webhook notification attempts
+const operationNotify = "notify"
+
+// MetricsConfig selects where Prometheus metrics are exported
+type MetricsConfig struct {
+	File string `json:"file,omitempty"`
+	Addr string `json:"addr,omitempty"`
+}
+
+// enabled reports whether metrics are exported anywhere
+func (m MetricsConfig) enabled() bool {
+	return m.File != "" || m.Addr != ""
+}
+
+// Metrics records token fetches, submissions, retry attempts and circuit
+// breaker transitions. A nil *Metrics records nothing.
+type Metrics struct {
+	config   MetricsConfig
+	logger   *Logger
+	registry *metrics.Registry
+	server   *http.Server
+
+	tokenFetches       *metrics.CounterVec
+	tokenFetchDuration *metrics.HistogramVec
+	submissions        *metrics.CounterVec
+	submissionDuration *metrics.HistogramVec
+	attempts           *metrics.CounterVec
+	breakerTransitions *metrics.CounterVec
+	breakerState       *metrics.GaugeVec
+	lastRun            *metrics.GaugeVec
+}
+
+// NewMetrics creates the metrics for config, or returns nil when metrics are
+// disabled. Counters continue from an existing textfile so that scheduled runs
+// accumulate.
+func NewMetrics(config MetricsConfig, logger *Logger) *Metrics {
+	if !config.enabled() {
+		return nil
+	}
+
+	registry := metrics.NewRegistry()
+	m := &Metrics{
+		config:   config,
+		logger:   logger,
+		registry: registry,
+		tokenFetches: registry.Counter("micv_token_fetches_total",
+			"Token fetches from the secret endpoint, after retries.", "outcome"),
+		tokenFetchDuration: registry.Histogram("micv_token_fetch_duration_seconds",
+			"Time to fetch a token, including retries.", metrics.DefaultBuckets),
+		submissions: registry.Counter("micv_submissions_total",
+			"Application submissions, after retries, by final HTTP status.", "outcome", "status_code"),
+		submissionDuration: registry.Histogram("micv_submission_duration_seconds",
+			"Time to submit an application, including retries.", metrics.DefaultBuckets),
+		attempts: registry.Counter("micv_request_attempts_total",
+			"Individual request attempts, including retries.", "operation", "outcome"),
+		breakerTransitions: registry.Counter("micv_circuit_breaker_transitions_total",
+			"Circuit breaker state transitions, by new state.", "state"),
+		breakerState: registry.Gauge("micv_circuit_breaker_state",
+			"Current circuit breaker state (0 closed, 1 open, 2 half-open)."),
+		lastRun: registry.Gauge("micv_last_run_timestamp_seconds",
+			"Unix time at which the last run finished, by outcome.", "outcome"),
+	}
+
+	if config.File != "" {
+		if file, err := os.Open(config.File); err == nil {
+			if err := registry.Load(file); err != nil {
+				logger.Warn("Ignoring unreadable metrics file", "file", config.File, "error", err)
+			}
+			file.Close()
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("Failed to read metrics file", "file", config.File, "error", err)
+		}
+	}
+
+	// Every run starts with a closed circuit breaker
+	m.breakerState.Set(float64(CircuitClosed))
+	return m
+}
+
+// Start serves the metrics on the configured address, if any, until Finish
+func (m *Metrics) Start() error {
+	if m == nil || m.config.Addr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", m.config.Addr)
+	if err != nil {
+		return WrapConfigError(fmt.Errorf("failed to listen for metrics: %w", err), "metrics.addr")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.registry.Handler())
+	m.server = &http.Server{Addr: listener.Addr().String(), Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go m.server.Serve(listener)
+
+	fmt.Printf("📈 Serving metrics on http://%s/metrics\n", listener.Addr())
+	return nil
+}
+
+// Finish records the outcome of the run, writes the textfile and stops the
+// metrics server. Failures to export are reported but do not fail the run.
+func (m *Metrics) Finish(runErr error) {
+	if m == nil {
+		return
+	}
+
+	outcome := "success"
+	if runErr != nil {
+		outcome = "failure"
+	}
+	m.lastRun.Set(float64(time.Now().Unix()), outcome)
+
+	if m.config.File != "" {
+		if err := m.registry.WriteFile(m.config.File); err != nil {
+			m.logger.Warn("Failed to write metrics file", "file", m.config.File, "error", err)
+			fmt.Printf("⚠️  Failed to write metrics to %s: %v\n", m.config.File, err)
+		}
+	}
+
+	if m.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		m.server.Shutdown(ctx)
+	}
+}
+
+// Attempt implements client.Observer
+func (m *Metrics) Attempt(operation string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.attempts.Inc(operation, outcomeLabel(err))
+}
+
+// TokenFetched implements client.Observer
+func (m *Metrics) TokenFetched(duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.tokenFetches.Inc(outcomeLabel(err))
+	m.tokenFetchDuration.Observe(duration.Seconds())
+}
+
+// Submitted implements client.Observer
+func (m *Metrics) Submitted(duration time.Duration, resp *client.SubmitResponse, err error) {
+	if m == nil {
+		return
+	}
+
+	outcome, statusCode := outcomeLabel(err), "none"
+	if resp != nil {
+		statusCode = strconv.Itoa(resp.StatusCode)
+	}
+	if err == nil && !resp.Succeeded() {
+		outcome = "failure"
+	}
+	m.submissions.Inc(outcome, statusCode)
+	m.submissionDuration.Observe(duration.Seconds())
+}
+
+// CircuitStateChanged records a circuit breaker transition
+func (m *Metrics) CircuitStateChanged(from, to CircuitState) {
+	if m == nil {
+		return
+	}
+	m.breakerTransitions.Inc(to.String())
+	m.breakerState.Set(float64(to))
+}
+
+// outcomeLabel returns the outcome label of an operation
+func outcomeLabel(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
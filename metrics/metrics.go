@@ -0,0 +1,172 @@
+// Package metrics records counters, gauges and histograms and writes them in
+// the Prometheus text exposition format, to a textfile for node_exporter's
+// textfile collector or over HTTP.
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Metric types in the exposition format
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// DefaultBuckets are latency buckets in seconds suited to HTTP requests
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Registry holds metric families in registration order
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+	byName   map[string]*family
+}
+
+// family is a metric with all of its labelled series
+type family struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*series
+}
+
+// series is one labelled time series. Histogram bucket counts are cumulative.
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []float64
+	sum         float64
+	count       float64
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]*family)}
+}
+
+// register adds a family, or returns the existing family of the same name
+func (r *Registry) register(name, help, typ string, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.byName[name]; ok {
+		return f
+	}
+	f := &family{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.families = append(r.families, f)
+	r.byName[name] = f
+	return f
+}
+
+// seriesFor returns the series for the label values, creating it if needed.
+// The registry lock must be held.
+func (f *family) seriesFor(labelValues []string) *series {
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.typ == TypeHistogram {
+			s.counts = make([]float64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// sortedSeries returns the series ordered by label values
+func (f *family) sortedSeries() []*series {
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := make([]*series, len(keys))
+	for i, key := range keys {
+		list[i] = f.series[key]
+	}
+	return list
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct {
+	registry *Registry
+	family   *family
+}
+
+// Counter registers a counter with the given label names
+func (r *Registry) Counter(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{registry: r, family: r.register(name, help, TypeCounter, nil, labelNames)}
+}
+
+// Inc adds one to the series with the given label values
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds a non-negative value to the series with the given label values
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	c.registry.mu.Lock()
+	defer c.registry.mu.Unlock()
+	c.family.seriesFor(labelValues).value += value
+}
+
+// GaugeVec is a gauge partitioned by labels
+type GaugeVec struct {
+	registry *Registry
+	family   *family
+}
+
+// Gauge registers a gauge with the given label names
+func (r *Registry) Gauge(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{registry: r, family: r.register(name, help, TypeGauge, nil, labelNames)}
+}
+
+// Set sets the series with the given label values
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.registry.mu.Lock()
+	defer g.registry.mu.Unlock()
+	g.family.seriesFor(labelValues).value = value
+}
+
+// HistogramVec is a histogram partitioned by labels
+type HistogramVec struct {
+	registry *Registry
+	family   *family
+}
+
+// Histogram registers a histogram with the given upper bucket bounds, in increasing order
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return &HistogramVec{registry: r, family: r.register(name, help, TypeHistogram, buckets, labelNames)}
+}
+
+// Observe records a value in the series with the given label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.registry.mu.Lock()
+	defer h.registry.mu.Unlock()
+
+	s := h.family.seriesFor(labelValues)
+	for i, bound := range h.family.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
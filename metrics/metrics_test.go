@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestRegistry() (*Registry, *CounterVec, *GaugeVec, *HistogramVec) {
+	registry := NewRegistry()
+	counter := registry.Counter("test_requests_total", "Requests.", "outcome")
+	gauge := registry.Gauge("test_state", "State.")
+	histogram := registry.Histogram("test_duration_seconds", "Duration.", []float64{0.1, 1})
+	return registry, counter, gauge, histogram
+}
+
+func TestWriteText(t *testing.T) {
+	registry, counter, gauge, histogram := newTestRegistry()
+	counter.Inc("success")
+	counter.Add(2, "failure")
+	counter.Inc(`quote"d`)
+	gauge.Set(1)
+	histogram.Observe(0.05)
+	histogram.Observe(0.5)
+	histogram.Observe(3)
+
+	var buf bytes.Buffer
+	if err := registry.WriteText(&buf); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expected := `# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{outcome="failure"} 2
+test_requests_total{outcome="quote\"d"} 1
+test_requests_total{outcome="success"} 1
+# HELP test_state State.
+# TYPE test_state gauge
+test_state 1
+# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="0.1"} 1
+test_duration_seconds_bucket{le="1"} 2
+test_duration_seconds_bucket{le="+Inf"} 3
+test_duration_seconds_sum 3.55
+test_duration_seconds_count 3
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected exposition:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestLoadAccumulates(t *testing.T) {
+	previous, counter, gauge, histogram := newTestRegistry()
+	counter.Add(3, `quote"d`)
+	gauge.Set(1)
+	histogram.Observe(0.5)
+
+	var saved bytes.Buffer
+	if err := previous.WriteText(&saved); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	saved.WriteString("other_metric{a=\"b\"} 7\n")
+
+	registry, counter, gauge, histogram := newTestRegistry()
+	if err := registry.Load(&saved); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	counter.Inc(`quote"d`)
+	gauge.Set(0)
+	histogram.Observe(2)
+
+	var buf bytes.Buffer
+	registry.WriteText(&buf)
+	for _, line := range []string{
+		`test_requests_total{outcome="quote\"d"} 4`,
+		"test_state 0",
+		`test_duration_seconds_bucket{le="1"} 1`,
+		`test_duration_seconds_bucket{le="+Inf"} 2`,
+		"test_duration_seconds_sum 2.5",
+		"test_duration_seconds_count 2",
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("Expected %q in:\n%s", line, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "other_metric") {
+		t.Errorf("Expected unknown metrics to be dropped:\n%s", buf.String())
+	}
+}
+
+func TestLoadRejectsMalformedInput(t *testing.T) {
+	registry, _, _, _ := newTestRegistry()
+	if err := registry.Load(strings.NewReader("test_state{outcome=\"x} 1\n")); err == nil {
+		t.Error("Expected an error for an unterminated label value")
+	}
+	if err := registry.Load(strings.NewReader("test_state abc\n")); err == nil {
+		t.Error("Expected an error for an invalid value")
+	}
+}
+
+func TestWriteFileAndHandler(t *testing.T) {
+	registry, counter, _, _ := newTestRegistry()
+	counter.Inc("success")
+
+	filename := filepath.Join(t.TempDir(), "micv.prom")
+	if err := registry.WriteFile(filename); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Expected metrics file: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(filename))
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files to remain, got %d entries", len(entries))
+	}
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if recorder.Body.String() != string(data) {
+		t.Errorf("Expected handler to serve the file contents, got:\n%s", recorder.Body.String())
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %q", contentType)
+	}
+}
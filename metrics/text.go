@@ -0,0 +1,294 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// WriteText writes every metric in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range r.families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.typ)
+		for _, s := range f.sortedSeries() {
+			if f.typ != TypeHistogram {
+				fmt.Fprintf(bw, "%s%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, ""), formatValue(s.value))
+				continue
+			}
+			for i, bound := range f.buckets {
+				fmt.Fprintf(bw, "%s_bucket%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, formatValue(bound)), formatValue(s.counts[i]))
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, "+Inf"), formatValue(s.count))
+			fmt.Fprintf(bw, "%s_sum%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, ""), formatValue(s.sum))
+			fmt.Fprintf(bw, "%s_count%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, ""), formatValue(s.count))
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteFile atomically replaces filename with the current metrics, so that a
+// collector never reads a partial file
+func (r *Registry) WriteFile(filename string) error {
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace metrics file: %w", err)
+	}
+	return nil
+}
+
+// Handler serves the metrics in the text exposition format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// Load restores registered series from text previously written by WriteText,
+// so that counters keep increasing across runs that share a textfile.
+// Counters and histograms are added to, gauges are set, and unknown metrics
+// are ignored.
+func (r *Registry) Load(rd io.Reader) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scanner := bufio.NewScanner(rd)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, labels, value, err := parseSample(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		r.loadSample(name, labels, value)
+	}
+	return scanner.Err()
+}
+
+// loadSample applies one parsed sample to its registered family, if any.
+// The registry lock must be held.
+func (r *Registry) loadSample(name string, labels map[string]string, value float64) {
+	if f, ok := r.byName[name]; ok && f.typ != TypeHistogram {
+		labelValues, ok := orderLabels(f.labelNames, labels)
+		if !ok {
+			return
+		}
+		s := f.seriesFor(labelValues)
+		if f.typ == TypeCounter {
+			s.value += value
+		} else {
+			s.value = value
+		}
+		return
+	}
+
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		f, ok := r.byName[strings.TrimSuffix(name, suffix)]
+		if !strings.HasSuffix(name, suffix) || !ok || f.typ != TypeHistogram {
+			continue
+		}
+
+		le, hasLE := labels["le"]
+		delete(labels, "le")
+		labelValues, ok := orderLabels(f.labelNames, labels)
+		if !ok {
+			return
+		}
+		s := f.seriesFor(labelValues)
+
+		switch suffix {
+		case "_sum":
+			s.sum += value
+		case "_count":
+			s.count += value
+		case "_bucket":
+			// The +Inf bucket is the count, which has its own sample
+			if !hasLE || le == "+Inf" {
+				return
+			}
+			bound, err := strconv.ParseFloat(le, 64)
+			if err != nil {
+				return
+			}
+			for i, b := range f.buckets {
+				if b == bound {
+					s.counts[i] += value
+				}
+			}
+		}
+		return
+	}
+}
+
+// orderLabels returns the label values in the order of names, requiring an exact match
+func orderLabels(names []string, labels map[string]string) ([]string, bool) {
+	if len(labels) != len(names) {
+		return nil, false
+	}
+	values := make([]string, len(names))
+	for i, name := range names {
+		value, ok := labels[name]
+		if !ok {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+// parseSample parses `name{label="value",...} value [timestamp]`
+func parseSample(line string) (string, map[string]string, float64, error) {
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return "", nil, 0, fmt.Errorf("invalid sample %q", line)
+	}
+	name := line[:end]
+	rest := line[end:]
+
+	labels := make(map[string]string)
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		if rest, err = parseLabels(rest[1:], labels); err != nil {
+			return "", nil, 0, err
+		}
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, fmt.Errorf("missing value in %q", line)
+	}
+	value, err := parseValue(fields[0])
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("invalid value in %q", line)
+	}
+	return name, labels, value, nil
+}
+
+// parseLabels parses label pairs up to the closing brace and returns the rest of the line
+func parseLabels(s string, labels map[string]string) (string, error) {
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if strings.HasPrefix(s, "}") {
+			return s[1:], nil
+		}
+
+		eq := strings.Index(s, "=")
+		if eq <= 0 || len(s) < eq+2 || s[eq+1] != '"' {
+			return "", fmt.Errorf("invalid labels")
+		}
+		name := strings.TrimSpace(s[:eq])
+		s = s[eq+2:]
+
+		var value strings.Builder
+		closed := false
+		for i := 0; i < len(s); i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			if s[i] == '"' {
+				s = s[i+1:]
+				closed = true
+				break
+			}
+			value.WriteByte(s[i])
+		}
+		if !closed {
+			return "", fmt.Errorf("unterminated label value")
+		}
+		labels[name] = value.String()
+	}
+}
+
+// formatLabels renders label pairs, adding le for histogram buckets when set
+func formatLabels(names, values []string, le string) string {
+	if len(names) == 0 && le == "" {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabelValue(values[i])+`"`)
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// escapeLabelValue escapes backslashes, quotes and newlines in a label value
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// escapeHelp escapes backslashes and newlines in help text
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// formatValue renders a sample value
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// parseValue parses a sample value, including +Inf, -Inf and NaN
+func parseValue(s string) (float64, error) {
+	switch s {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
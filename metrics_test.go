@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readMetricsFile returns the textfile written by Metrics.Finish
+func readMetricsFile(t *testing.T, filename string) string {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Expected metrics file: %v", err)
+	}
+	return string(data)
+}
+
+// expectMetricLines fails unless every line appears in text
+func expectMetricLines(t *testing.T, text string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("Expected %q in metrics:\n%s", line, text)
+		}
+	}
+}
+
+func TestApplicationRunRecordsMetrics(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "micv.prom")
+
+	deps := NewMockDependencies()
+	deps.config.Retry = RetrySettings{MaxAttempts: 3, InitialDelayMS: 1, MaxDelayMS: 1}
+	deps.metrics = NewMetrics(MetricsConfig{File: filename}, deps.logger)
+	deps.circuitBreaker.OnStateChange(deps.metrics.CircuitStateChanged)
+
+	// The first token fetch fails so that it is retried
+	tokenCalls := 0
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		tokenCalls++
+		if tokenCalls == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return createResponse(201, `{"status":"success"}`), nil
+	}
+
+	appData := ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Software Engineer"}
+	err := NewApplication(deps).Run(context.Background(), appData)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	deps.metrics.Finish(err)
+
+	text := readMetricsFile(t, filename)
+	expectMetricLines(t, text,
+		`micv_token_fetches_total{outcome="success"} 1`,
+		`micv_token_fetch_duration_seconds_count 1`,
+		`micv_submissions_total{outcome="success",status_code="201"} 1`,
+		`micv_submission_duration_seconds_count 1`,
+		`micv_request_attempts_total{operation="fetch_token",outcome="failure"} 1`,
+		`micv_request_attempts_total{operation="fetch_token",outcome="success"} 1`,
+		`micv_request_attempts_total{operation="submit",outcome="success"} 1`,
+		`micv_circuit_breaker_state 0`,
+	)
+	if !strings.Contains(text, `micv_last_run_timestamp_seconds{outcome="success"} `) {
+		t.Errorf("Expected last run timestamp in metrics:\n%s", text)
+	}
+}
+
+func TestMetricsAccumulateAcrossRuns(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "micv.prom")
+	logger := NewLogger(LogLevelError)
+
+	for run := 0; run < 2; run++ {
+		metrics := NewMetrics(MetricsConfig{File: filename}, logger)
+		metrics.Submitted(time.Second, &SubmitResponse{StatusCode: 500}, nil)
+		metrics.Finish(nil)
+	}
+
+	expectMetricLines(t, readMetricsFile(t, filename),
+		`micv_submissions_total{outcome="failure",status_code="500"} 2`,
+		`micv_submission_duration_seconds_bucket{le="2.5"} 2`,
+		`micv_submission_duration_seconds_sum 2`,
+	)
+}
+
+func TestMetricsRecordCircuitBreakerTransitions(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "micv.prom")
+	logger := NewLogger(LogLevelError)
+	metrics := NewMetrics(MetricsConfig{File: filename}, logger)
+
+	breaker := NewCircuitBreaker(1, time.Millisecond, logger)
+	breaker.OnStateChange(metrics.CircuitStateChanged)
+
+	breaker.Call(context.Background(), func() error { return errors.New("failed") })
+	metrics.Finish(nil)
+	expectMetricLines(t, readMetricsFile(t, filename),
+		`micv_circuit_breaker_transitions_total{state="open"} 1`,
+		`micv_circuit_breaker_state 1`,
+	)
+
+	time.Sleep(5 * time.Millisecond)
+	breaker.Call(context.Background(), func() error { return nil })
+	metrics.Finish(nil)
+	expectMetricLines(t, readMetricsFile(t, filename),
+		`micv_circuit_breaker_transitions_total{state="half_open"} 1`,
+		`micv_circuit_breaker_transitions_total{state="closed"} 1`,
+		`micv_circuit_breaker_state 0`,
+	)
+}
+
+func TestMetricsServe(t *testing.T) {
+	metrics := NewMetrics(MetricsConfig{Addr: "127.0.0.1:0"}, NewLogger(LogLevelError))
+	if err := metrics.Start(); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	metrics.TokenFetched(time.Second, errors.New("failed"))
+
+	resp, err := http.Get("http://" + metrics.server.Addr + "/metrics")
+	if err != nil {
+		t.Fatalf("Expected metrics endpoint: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	expectMetricLines(t, string(body), `micv_token_fetches_total{outcome="failure"} 1`)
+
+	metrics.Finish(nil)
+	if _, err := http.Get("http://" + metrics.server.Addr + "/metrics"); err == nil {
+		t.Error("Expected the metrics server to stop after the run")
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	var metrics *Metrics = NewMetrics(MetricsConfig{}, NewLogger(LogLevelError))
+	if metrics != nil {
+		t.Fatal("Expected no metrics without a file or address")
+	}
+	// A nil *Metrics records nothing
+	metrics.Attempt("submit", time.Second, nil)
+	if err := metrics.Start(); err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+	metrics.Finish(nil)
+}
+
+func TestMetricsConfigFromFlagsAndEnvironment(t *testing.T) {
+	t.Setenv("MICV_METRICS_ADDR", "127.0.0.1:9464")
+
+	configResult, err := LoadConfigFromArgs([]string{"--metrics-file", "micv.prom", "submit", "John Doe", "john@example.com", "Engineer"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if configResult.Config.Metrics != (MetricsConfig{File: "micv.prom", Addr: "127.0.0.1:9464"}) {
+		t.Errorf("Unexpected metrics config %+v", configResult.Config.Metrics)
+	}
+	if source := configResult.Sources.Source("metrics.file"); source != "flag --metrics-file" {
+		t.Errorf("Expected metrics.file from the flag, got %q", source)
+	}
+
+	config := DefaultConfig()
+	config.Metrics.Addr = "9464"
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected an error for a metrics address without a port separator")
+	}
+}
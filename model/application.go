@@ -0,0 +1,46 @@
+// Package model defines the application payload, the functional Result type
+// and the structured errors shared by the micv packages.
+package model
+
+// SecretResponse represents the JSON structure returned by the secret endpoint
+type SecretResponse struct {
+	Result string `json:"result"`
+}
+
+// ApplicationData represents the JSON structure to be sent
+type ApplicationData struct {
+	Name             string       `json:"name"`
+	Email            string       `json:"email"`
+	JobTitle         string       `json:"job_title"`
+	FinalAttempt     *bool        `json:"final_attempt,omitempty"`
+	ExtraInformation interface{}  `json:"extra_information,omitempty"`
+	Attachments      []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment references a local file sent with the application as a
+// multipart/form-data part
+type Attachment struct {
+	Field       string `json:"field"`
+	Path        string `json:"path"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// ExtraInfo represents additional information about the candidate
+type ExtraInfo struct {
+	PersonalAttributes []string   `json:"personal_attributes"`
+	Experience         Experience `json:"experience"`
+	WhyHireMe          string     `json:"why_hire_me"`
+	TechnicalSkills    []string   `json:"technical_skills"`
+	Education          string     `json:"education"`
+	Location           string     `json:"location"`
+	Availability       string     `json:"availability"`
+}
+
+// Experience represents professional experience information
+type Experience struct {
+	YearsOfExperience int      `json:"years_of_experience"`
+	PreviousRoles     []string `json:"previous_roles"`
+	KeyProjects       []string `json:"key_projects"`
+	Languages         []string `json:"programming_languages"`
+	Frameworks        []string `json:"frameworks"`
+}
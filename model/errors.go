@@ -0,0 +1,82 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// AppError represents application-specific errors with context
+type AppError struct {
+	Code      string
+	Message   string
+	Cause     error
+	Context   map[string]interface{}
+	Timestamp time.Time
+}
+
+// Error implements the error interface
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("[%s] %s: %v", e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+}
+
+// Unwrap returns the underlying error
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// NewAppError creates a new application error
+func NewAppError(code, message string, cause error) *AppError {
+	return &AppError{
+		Code:      code,
+		Message:   message,
+		Cause:     cause,
+		Context:   make(map[string]interface{}),
+		Timestamp: time.Now(),
+	}
+}
+
+// WithContext adds context to the error
+func (e *AppError) WithContext(key string, value interface{}) *AppError {
+	e.Context[key] = value
+	return e
+}
+
+// Error codes for better error categorization
+const (
+	ErrCodeNetwork     = "NETWORK_ERROR"
+	ErrCodeValidation  = "VALIDATION_ERROR"
+	ErrCodeConfig      = "CONFIG_ERROR"
+	ErrCodeAuth        = "AUTH_ERROR"
+	ErrCodeApplication = "APPLICATION_ERROR"
+	ErrCodeParsing     = "PARSING_ERROR"
+	ErrCodeTimeout     = "TIMEOUT_ERROR"
+	ErrCodeUnexpected  = "UNEXPECTED_ERROR"
+)
+
+// Enhanced error handling functions
+func WrapNetworkError(err error, url string) *AppError {
+	return NewAppError(ErrCodeNetwork, "Network request failed", err).
+		WithContext("url", url).
+		WithContext("retry_suggested", true)
+}
+
+func WrapValidationError(err error, field string) *AppError {
+	return NewAppError(ErrCodeValidation, "Validation failed", err).
+		WithContext("field", field).
+		WithContext("user_action_required", true)
+}
+
+func WrapConfigError(err error, configPath string) *AppError {
+	return NewAppError(ErrCodeConfig, "Configuration error", err).
+		WithContext("config_path", configPath).
+		WithContext("check_config_file", true)
+}
+
+func WrapAuthError(err error, endpoint string) *AppError {
+	return NewAppError(ErrCodeAuth, "Authentication failed", err).
+		WithContext("endpoint", endpoint).
+		WithContext("check_credentials", true)
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAppError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := WrapNetworkError(cause, "https://example.com")
+
+	if err.Error() != "[NETWORK_ERROR] Network request failed: connection refused" {
+		t.Errorf("Unexpected message: %s", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected AppError to unwrap to its cause")
+	}
+	if err.Context["url"] != "https://example.com" || err.Context["retry_suggested"] != true {
+		t.Errorf("Unexpected context: %v", err.Context)
+	}
+	if err.Timestamp.IsZero() {
+		t.Error("Expected a timestamp")
+	}
+
+	if got := NewAppError(ErrCodeApplication, "Rejected", nil).Error(); got != "[APPLICATION_ERROR] Rejected" {
+		t.Errorf("Unexpected message without cause: %s", got)
+	}
+}
+
+func TestWrapErrors(t *testing.T) {
+	cause := errors.New("cause")
+	tests := []struct {
+		err     *AppError
+		code    string
+		context string
+		value   string
+	}{
+		{WrapValidationError(cause, "email"), ErrCodeValidation, "field", "email"},
+		{WrapConfigError(cause, "tls"), ErrCodeConfig, "config_path", "tls"},
+		{WrapAuthError(cause, "https://example.com/secret"), ErrCodeAuth, "endpoint", "https://example.com/secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if tt.err.Code != tt.code || tt.err.Context[tt.context] != tt.value || tt.err.Cause != cause {
+				t.Errorf("Unexpected error %+v", tt.err)
+			}
+		})
+	}
+}
+
+func TestResult(t *testing.T) {
+	double := func(n int) int { return n * 2 }
+
+	ok := NewResult(2).Map(double)
+	if !ok.IsSuccess() || ok.Value != 4 {
+		t.Errorf("Expected mapped value 4, got %+v", ok)
+	}
+
+	failed := NewError[int](errors.New("failed")).Map(double)
+	if !failed.IsError() || failed.OrElse(7) != 7 {
+		t.Errorf("Expected error result to skip Map and use the alternative, got %+v", failed)
+	}
+
+	filtered := NewResult(3).Filter(func(n int) bool { return n%2 == 0 }, "must be even")
+	if filtered.IsSuccess() || filtered.Error.Error() != "must be even" {
+		t.Errorf("Expected filter error, got %+v", filtered)
+	}
+
+	chained := NewResult(5).FlatMap(func(n int) Result[int] {
+		if n > 3 {
+			return NewError[int](errors.New("too large"))
+		}
+		return NewResult(n)
+	})
+	if chained.IsSuccess() || chained.OrElse(0) != 0 {
+		t.Errorf("Expected FlatMap error, got %+v", chained)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	var ran []string
+	step := func(name string, fail bool) func(string) Result[string] {
+		return func(s string) Result[string] {
+			ran = append(ran, name)
+			if fail {
+				return NewError[string](errors.New(name + " failed"))
+			}
+			return NewResult(strings.ToUpper(s))
+		}
+	}
+
+	result := NewPipeline[string]().Add(step("upper", false)).Execute("micv")
+	if result.Value != "MICV" {
+		t.Errorf("Expected MICV, got %+v", result)
+	}
+
+	ran = nil
+	result = NewPipeline[string]().
+		Add(step("first", false)).
+		Add(step("second", true)).
+		Add(step("third", false)).
+		Execute("micv")
+	if result.IsSuccess() || strings.Join(ran, ",") != "first,second" {
+		t.Errorf("Expected the pipeline to stop at the failing step, ran %v", ran)
+	}
+}
+
+func TestComposeAndCurry(t *testing.T) {
+	length := Compose(strconv.Itoa, func(s string) int { return len(s) })
+	if got := length("micv"); got != "4" {
+		t.Errorf("Expected composed result 4, got %s", got)
+	}
+
+	add := Curry(func(a, b int) int { return a + b })
+	if got := add(2)(3); got != 5 {
+		t.Errorf("Expected curried result 5, got %d", got)
+	}
+}
+
+func TestApplicationDataJSON(t *testing.T) {
+	finalAttempt := true
+	data := ApplicationData{
+		Name:         "John Doe",
+		Email:        "john@example.com",
+		JobTitle:     "Software Engineer",
+		FinalAttempt: &finalAttempt,
+		Attachments:  []Attachment{{Field: "resume", Path: "resume.pdf"}},
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	expected := `{"name":"John Doe","email":"john@example.com","job_title":"Software Engineer","final_attempt":true,"attachments":[{"field":"resume","path":"resume.pdf"}]}`
+	if string(encoded) != expected {
+		t.Errorf("Expected %s, got %s", expected, encoded)
+	}
+
+	minimal, _ := json.Marshal(ApplicationData{Name: "John Doe"})
+	if strings.Contains(string(minimal), "final_attempt") || strings.Contains(string(minimal), "attachments") {
+		t.Errorf("Expected optional fields to be omitted, got %s", minimal)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultNotificationTimeout bounds each webhook request when no timeout is configured
+const defaultNotificationTimeout = 10 * time.Second
+
+// NotificationConfig lists the webhooks notified after each run, by name
+type NotificationConfig struct {
+	Webhooks       map[string]string `json:"webhooks,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+}
+
+// timeout returns the time limit for a single webhook request
+func (n NotificationConfig) timeout() time.Duration {
+	if n.TimeoutSeconds > 0 {
+		return time.Duration(n.TimeoutSeconds) * time.Second
+	}
+	return defaultNotificationTimeout
+}
+
+// Notification is the JSON summary posted to each webhook
+type Notification struct {
+	RunID          string    `json:"run_id"`
+	Applicant      string    `json:"applicant"`
+	Email          string    `json:"email"`
+	JobTitle       string    `json:"job_title"`
+	FinalAttempt   bool      `json:"final_attempt"`
+	ApplicationURL string    `json:"application_url"`
+	Succeeded      bool      `json:"succeeded"`
+	StatusCode     int       `json:"status_code,omitempty"`
+	ErrorCode      string    `json:"error_code,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	CompletedAt    time.Time `json:"completed_at"`
+}
+
+// newNotification summarises the outcome of a run
+func newNotification(runID, applicationURL string, appData ApplicationData, resp *SubmitResponse, err error) Notification {
+	notification := Notification{
+		RunID:          runID,
+		Applicant:      appData.Name,
+		Email:          appData.Email,
+		JobTitle:       appData.JobTitle,
+		FinalAttempt:   appData.FinalAttempt != nil && *appData.FinalAttempt,
+		ApplicationURL: applicationURL,
+		Succeeded:      err == nil && resp.Succeeded(),
+		CompletedAt:    time.Now().UTC(),
+	}
+	if resp != nil {
+		notification.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		notification.Error = err.Error()
+		notification.ErrorCode = ErrCodeUnexpected
+		var appErr *AppError
+		if errors.As(err, &appErr) {
+			notification.ErrorCode = appErr.Code
+		}
+	}
+	return notification
+}
+
+// newRunID returns a random identifier for one run of the application
+func newRunID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
+
+// Notifier posts notifications to the configured webhooks
+type Notifier struct {
+	config  NotificationConfig
+	client  HTTPClient
+	retry   RetryConfig
+	logger  *Logger
+	metrics *Metrics
+}
+
+// NewNotifier creates a notifier for the configured webhooks. Webhooks use a
+// plain HTTP client rather than the proxy, TLS and record/replay settings of
+// the application endpoints. Attempts are recorded in metrics, which may be nil.
+func NewNotifier(config NotificationConfig, retry RetryConfig, logger *Logger, metrics *Metrics) *Notifier {
+	return &Notifier{
+		config:  config,
+		client:  NewHTTPClientWithTimeout(config.timeout()),
+		retry:   retry,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// Notify posts the notification to every webhook concurrently; failures are
+// reported but do not affect the run
+func (n *Notifier) Notify(ctx context.Context, notification Notification) {
+	if len(n.config.Webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		n.logger.Warn("Failed to encode notification", "error", err)
+		return
+	}
+
+	// Notify even if the run used up its deadline
+	ctx = context.WithoutCancel(ctx)
+
+	names := make([]string, 0, len(n.config.Webhooks))
+	for name := range n.config.Webhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = n.send(ctx, name, n.config.Webhooks[name], body)
+		}()
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			n.logger.Warn("Webhook notification failed", "webhook", name, "error", errs[i])
+			fmt.Printf("⚠️  Notification to %s failed: %v\n", name, errs[i])
+		} else {
+			fmt.Printf("📣 Notified %s\n", name)
+		}
+	}
+}
+
+// send posts body to a single webhook with retries
+func (n *Notifier) send(ctx context.Context, name, url string, body []byte) error {
+	logger := n.logger.With("operation", "notify", "webhook", name)
+
+	return WithRetry(ctx, n.retry, logger, func() (err error) {
+		start := time.Now()
+		defer func() { n.metrics.Attempt(operationNotify, time.Since(start), err) }()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.client.Do(req)
+		if err != nil {
+			return WrapNetworkError(err, url)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		err = fmt.Errorf("webhook returned %s", resp.Status)
+		// Client errors other than rate limiting will not succeed on retry
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return Permanent(err)
+		}
+		return err
+	})
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestApplicationRunNotifiesWebhooks(t *testing.T) {
+	received := make(chan Notification, 2)
+	var relayCalls atomic.Int32
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt fails so that the notification is retried
+		if relayCalls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var notification Notification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			t.Errorf("Invalid notification: %v", err)
+		}
+		received <- notification
+	}))
+	defer relay.Close()
+
+	var rejectedCalls atomic.Int32
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rejectedCalls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer rejecting.Close()
+
+	deps := NewMockDependencies()
+	deps.config.Retry = RetrySettings{MaxAttempts: 3, InitialDelayMS: 1, MaxDelayMS: 1}
+	deps.config.Notifications.Webhooks = map[string]string{
+		"relay":   relay.URL,
+		"missing": rejecting.URL,
+	}
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return createResponse(201, `{"status":"success"}`), nil
+	}
+
+	finalAttempt := true
+	appData := ApplicationData{
+		Name:         "John Doe",
+		Email:        "john@example.com",
+		JobTitle:     "Software Engineer",
+		FinalAttempt: &finalAttempt,
+	}
+	if err := NewApplication(deps).Run(context.Background(), appData); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	select {
+	case notification := <-received:
+		if notification.RunID == "" || notification.Applicant != "John Doe" || notification.JobTitle != "Software Engineer" {
+			t.Errorf("Unexpected notification %+v", notification)
+		}
+		if !notification.FinalAttempt || !notification.Succeeded || notification.StatusCode != 201 || notification.ErrorCode != "" {
+			t.Errorf("Expected successful final attempt, got %+v", notification)
+		}
+	default:
+		t.Fatal("Expected a notification after retrying")
+	}
+	if rejectedCalls.Load() != 1 {
+		t.Errorf("Expected a 404 webhook not to be retried, got %d calls", rejectedCalls.Load())
+	}
+}
+
+func TestNewNotification(t *testing.T) {
+	appData := ApplicationData{Name: "John Doe", JobTitle: "Software Engineer"}
+
+	err := WrapAuthError(errors.New("denied"), "https://example.com/secret")
+	notification := newNotification("run-1", "https://example.com/apply", appData, nil, err)
+	if notification.Succeeded || notification.ErrorCode != ErrCodeAuth || notification.StatusCode != 0 {
+		t.Errorf("Expected auth failure, got %+v", notification)
+	}
+
+	notification = newNotification("run-1", "https://example.com/apply", appData, &SubmitResponse{StatusCode: 500}, nil)
+	if notification.Succeeded || notification.StatusCode != 500 || notification.ErrorCode != "" {
+		t.Errorf("Expected non-success status without error code, got %+v", notification)
+	}
+
+	notification = newNotification("run-1", "https://example.com/apply", appData, nil, errors.New("boom"))
+	if notification.ErrorCode != ErrCodeUnexpected {
+		t.Errorf("Expected %s for plain errors, got %q", ErrCodeUnexpected, notification.ErrorCode)
+	}
+}
+
+func TestNotificationConfigValidation(t *testing.T) {
+	config := DefaultConfig()
+	config.Notifications.Webhooks = map[string]string{"relay": "http://localhost:9000/micv"}
+	if err := ValidateConfig(config); err != nil {
+		t.Errorf("Expected loopback webhook to be valid, got %v", err)
+	}
+
+	// Webhooks never carry the token, so plain HTTP needs no --allow-insecure
+	config.Notifications.Webhooks["team"] = "http://chat.example.com/hook"
+	if err := ValidateConfig(config); err != nil {
+		t.Errorf("Expected plain HTTP webhook on a remote host to be valid, got %v", err)
+	}
+
+	config.Notifications.Webhooks["team"] = "chat.example.com/hook"
+	if err := ValidateConfig(config); err == nil || strings.Contains(err.Error(), "token") {
+		t.Errorf("Expected a webhook URL error that does not mention the token, got %v", err)
+	}
+
+	config.Notifications = NotificationConfig{TimeoutSeconds: -1}
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected negative notification timeout to be rejected")
+	}
+	if got := (NotificationConfig{}).timeout(); got != 10*time.Second {
+		t.Errorf("Expected default timeout of 10s, got %s", got)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// configProfiles holds the named profiles of a configuration file
+type configProfiles struct {
+	Profiles map[string]json.RawMessage `json:"profiles"`
+}
+
+// profileHeader holds the inheritance link of a single profile
+type profileHeader struct {
+	Extends string `json:"extends"`
+}
+
+// profileFromEnvironment returns the profile selected by MICV_PROFILE
+func profileFromEnvironment() string {
+	return strings.TrimSpace(os.Getenv("MICV_PROFILE"))
+}
+
+// applyConfigProfile overlays the named profile, and the profiles it extends,
+// onto config. Fields a profile leaves unset keep their inherited values.
+func applyConfigProfile(data []byte, name string, config *Config) error {
+	var file configProfiles
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to decode config profiles: %w", err)
+	}
+
+	chain, err := resolveProfileChain(file.Profiles, name)
+	if err != nil {
+		return err
+	}
+
+	// Apply the root ancestor first so that each profile overrides its parent
+	for i := len(chain) - 1; i >= 0; i-- {
+		if err := json.Unmarshal(file.Profiles[chain[i]], config); err != nil {
+			return fmt.Errorf("failed to decode profile %q: %w", chain[i], err)
+		}
+	}
+
+	return nil
+}
+
+// resolveProfileChain returns the profile followed by its ancestors
+func resolveProfileChain(profiles map[string]json.RawMessage, name string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+
+	for current := name; current != ""; {
+		if seen[current] {
+			return nil, fmt.Errorf("profile %q has circular inheritance: %s", name, strings.Join(append(chain, current), " -> "))
+		}
+		seen[current] = true
+
+		raw, ok := profiles[current]
+		if !ok {
+			if len(chain) > 0 {
+				return nil, fmt.Errorf("profile %q extends unknown profile %q", chain[len(chain)-1], current)
+			}
+			return nil, fmt.Errorf("unknown profile %q (available: %s)", current, strings.Join(profileNames(profiles), ", "))
+		}
+		chain = append(chain, current)
+
+		var header profileHeader
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return nil, fmt.Errorf("failed to decode profile %q: %w", current, err)
+		}
+		current = header.Extends
+	}
+
+	return chain, nil
+}
+
+// profileNames returns the sorted profile names
+func profileNames(profiles map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const profileConfigContent = `{
+  "secret_url": "https://au.mitimes.com/careers/apply/secret",
+  "application_url": "https://au.mitimes.com/careers/apply",
+  "timeout_seconds": 30,
+  "tls": { "min_version": "1.2" },
+  "profiles": {
+    "local": {
+      "secret_url": "http://localhost:8080/secret",
+      "application_url": "http://localhost:8080/apply",
+      "retry": { "max_attempts": 1 }
+    },
+    "staging-mock": {
+      "extends": "local",
+      "timeout_seconds": 5,
+      "tls": { "insecure_skip_verify": true }
+    },
+    "loop-a": { "extends": "loop-b" },
+    "loop-b": { "extends": "loop-a" },
+    "orphan": { "extends": "missing" }
+  }
+}`
+
+func writeProfileConfig(t *testing.T) string {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configFile, []byte(profileConfigContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	return configFile
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	configFile := writeProfileConfig(t)
+
+	config := DefaultConfig()
+	if err := loadConfigProfileFromFile(configFile, "staging-mock", config); err != nil {
+		t.Fatalf("loadConfigProfileFromFile failed: %v", err)
+	}
+
+	// Inherited from local
+	if config.SecretURL != "http://localhost:8080/secret" {
+		t.Errorf("Expected SecretURL from local profile, got '%s'", config.SecretURL)
+	}
+	if config.Retry.MaxAttempts != 1 {
+		t.Errorf("Expected retry attempts from local profile, got %d", config.Retry.MaxAttempts)
+	}
+	// Set by staging-mock
+	if config.Timeout != 5 {
+		t.Errorf("Expected Timeout 5, got %d", config.Timeout)
+	}
+	// Nested settings merge with the base
+	if !config.TLS.InsecureSkipVerify || config.TLS.MinVersion != "1.2" {
+		t.Errorf("Expected TLS settings to merge with base, got %+v", config.TLS)
+	}
+
+	base := DefaultConfig()
+	if err := loadConfigFromFile(configFile, base); err != nil {
+		t.Fatalf("loadConfigFromFile failed: %v", err)
+	}
+	if base.SecretURL != "https://au.mitimes.com/careers/apply/secret" || base.Timeout != 30 {
+		t.Errorf("Expected base configuration without a profile, got %+v", base)
+	}
+}
+
+func TestLoadConfigProfileErrors(t *testing.T) {
+	configFile := writeProfileConfig(t)
+
+	tests := []struct {
+		profile  string
+		contains string
+	}{
+		{"production", "unknown profile"},
+		{"loop-a", "circular inheritance"},
+		{"orphan", "extends unknown profile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			err := loadConfigProfileFromFile(configFile, tt.profile, DefaultConfig())
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestConfigRetryConfig(t *testing.T) {
+	config := DefaultConfig()
+	if config.RetryConfig() != DefaultRetryConfig() {
+		t.Error("Expected default retry configuration without overrides")
+	}
+
+	config.Retry = RetrySettings{MaxAttempts: 5, InitialDelayMS: 100}
+	retry := config.RetryConfig()
+	if retry.MaxAttempts != 5 || retry.InitialDelay != 100*time.Millisecond {
+		t.Errorf("Expected retry overrides to apply, got %+v", retry)
+	}
+	if retry.MaxDelay != DefaultRetryConfig().MaxDelay {
+		t.Errorf("Expected default max delay, got %v", retry.MaxDelay)
+	}
+}
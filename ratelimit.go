@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// defaultRateLimitHost is the rate limit key that applies to hosts without their own entry
+const defaultRateLimitHost = "*"
+
+// RateLimit configures a token bucket for outbound requests
+type RateLimit struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst,omitempty"`
+}
+
+// TokenBucket is a token-bucket rate limiter safe for concurrent use
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewTokenBucket creates a full bucket refilling at rate tokens per second
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// reserve takes a token if available, otherwise returns how long to wait for one
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available or ctx is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		delay := b.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// RateLimitedClient decorates an HTTPClient with per-host token buckets
+type RateLimitedClient struct {
+	next     HTTPClient
+	limits   map[string]RateLimit
+	mu       sync.Mutex
+	limiters map[string]*TokenBucket
+}
+
+// NewRateLimitedClient creates a client that throttles requests per host.
+// The "*" entry applies to hosts without their own limit.
+func NewRateLimitedClient(next HTTPClient, limits map[string]RateLimit) *RateLimitedClient {
+	return &RateLimitedClient{
+		next:     next,
+		limits:   limits,
+		limiters: make(map[string]*TokenBucket),
+	}
+}
+
+func (c *RateLimitedClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+func (c *RateLimitedClient) Do(req *http.Request) (*http.Response, error) {
+	if limiter := c.limiterFor(req.URL); limiter != nil {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("rate limiter: %w", err)
+		}
+	}
+	return c.next.Do(req)
+}
+
+// limiterFor returns the shared token bucket for the request host, if limited
+func (c *RateLimitedClient) limiterFor(u *url.URL) *TokenBucket {
+	host := u.Hostname()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if limiter, ok := c.limiters[host]; ok {
+		return limiter
+	}
+
+	limit, ok := c.limits[host]
+	if !ok {
+		limit, ok = c.limits[defaultRateLimitHost]
+	}
+	if !ok || limit.RequestsPerSecond <= 0 {
+		c.limiters[host] = nil
+		return nil
+	}
+
+	limiter := NewTokenBucket(limit.RequestsPerSecond, limit.Burst)
+	c.limiters[host] = limiter
+	return limiter
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	bucket := NewTokenBucket(2, 2)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket.now = func() time.Time { return now }
+	bucket.last = now
+
+	// Burst is available immediately
+	for i := 0; i < 2; i++ {
+		if delay := bucket.reserve(); delay != 0 {
+			t.Fatalf("Expected token %d to be available immediately, got delay %v", i, delay)
+		}
+	}
+
+	// Bucket is empty: next token arrives after 1/rate seconds
+	if delay := bucket.reserve(); delay != 500*time.Millisecond {
+		t.Errorf("Expected 500ms delay, got %v", delay)
+	}
+
+	// Refill never exceeds burst
+	now = now.Add(10 * time.Second)
+	for i := 0; i < 2; i++ {
+		if delay := bucket.reserve(); delay != 0 {
+			t.Fatalf("Expected refilled token %d, got delay %v", i, delay)
+		}
+	}
+	if delay := bucket.reserve(); delay == 0 {
+		t.Error("Expected bucket to be capped at burst size")
+	}
+}
+
+func TestTokenBucketWaitCancelled(t *testing.T) {
+	bucket := NewTokenBucket(0.001, 1)
+	bucket.reserve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := bucket.Wait(ctx); err == nil {
+		t.Error("Expected context error while waiting for a token")
+	}
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	var mu sync.Mutex
+	var calls []time.Time
+	next := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			calls = append(calls, time.Now())
+			mu.Unlock()
+			return createResponse(200, "ok"), nil
+		},
+	}
+
+	client := NewRateLimitedClient(next, map[string]RateLimit{
+		"limited.example.com": {RequestsPerSecond: 20, Burst: 1},
+	})
+
+	// Unlimited host is not throttled and does not share the limited bucket
+	if client.limiterFor(mustParseURL(t, "https://free.example.com/")) != nil {
+		t.Error("Expected no limiter for host without a rate limit")
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Get("https://limited.example.com/secret")
+		}()
+	}
+	wg.Wait()
+
+	// Three requests at 20/s with burst 1 need at least two refill intervals
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected requests to be throttled, took %v", elapsed)
+	}
+	if len(calls) != 3 {
+		t.Errorf("Expected 3 calls, got %d", len(calls))
+	}
+}
+
+func TestRateLimitedClientDefaultHost(t *testing.T) {
+	client := NewRateLimitedClient(&MockHTTPClient{}, map[string]RateLimit{
+		defaultRateLimitHost: {RequestsPerSecond: 5},
+	})
+
+	first := client.limiterFor(mustParseURL(t, "https://a.example.com/"))
+	second := client.limiterFor(mustParseURL(t, "https://b.example.com/"))
+	if first == nil || second == nil {
+		t.Fatal("Expected default limit to apply to every host")
+	}
+	if first == second {
+		t.Error("Expected each host to get its own bucket")
+	}
+	if client.limiterFor(mustParseURL(t, "https://a.example.com/other")) != first {
+		t.Error("Expected requests to the same host to share a bucket")
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("Failed to parse URL %s: %v", raw, err)
+	}
+	return u
+}
@@ -0,0 +1,237 @@
+// Package resilience provides the circuit breaker and retry-with-backoff
+// helpers used around outbound requests.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"micv/model"
+)
+
+// Logger is the logging interface used by the circuit breaker and retries
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+}
+
+// NopLogger discards all log messages
+type NopLogger struct{}
+
+func (NopLogger) Debug(msg string, fields ...interface{}) {}
+func (NopLogger) Info(msg string, fields ...interface{})  {}
+func (NopLogger) Warn(msg string, fields ...interface{})  {}
+func (NopLogger) Error(msg string, fields ...interface{}) {}
+
+// Circuit breaker pattern for resilient HTTP calls
+type CircuitBreaker struct {
+	maxFailures  int
+	resetTimeout time.Duration
+	failures     int
+	lastFailTime time.Time
+	state        CircuitState
+	logger       Logger
+	onChange     func(from, to CircuitState)
+	mu           sync.Mutex
+}
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// String returns the state name used in logs and metrics
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// NewCircuitBreaker creates a new circuit breaker
+func NewCircuitBreaker(maxFailures int, resetTimeout time.Duration, logger Logger) *CircuitBreaker {
+	return &CircuitBreaker{
+		maxFailures:  maxFailures,
+		resetTimeout: resetTimeout,
+		state:        CircuitClosed,
+		logger:       logger,
+	}
+}
+
+// OnStateChange registers fn to be called on every state transition. fn runs
+// while the breaker is locked and must not call back into it.
+func (cb *CircuitBreaker) OnStateChange(fn func(from, to CircuitState)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onChange = fn
+}
+
+// setState moves to state, notifying the listener if it changed
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	if cb.state == state {
+		return
+	}
+	from := cb.state
+	cb.state = state
+	if cb.onChange != nil {
+		cb.onChange(from, state)
+	}
+}
+
+// Call executes a function with circuit breaker protection
+func (cb *CircuitBreaker) Call(ctx context.Context, fn func() error) error {
+	cb.mu.Lock()
+	if cb.state == CircuitOpen {
+		if time.Since(cb.lastFailTime) > cb.resetTimeout {
+			cb.setState(CircuitHalfOpen)
+			cb.logger.Info("Circuit breaker transitioning to half-open state")
+		} else {
+			cb.mu.Unlock()
+			cb.logger.Warn("Circuit breaker is open, rejecting call")
+			return model.NewAppError(model.ErrCodeTimeout, "Circuit breaker is open", nil)
+		}
+	}
+	cb.mu.Unlock()
+
+	err := fn()
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if err != nil {
+		cb.onFailure()
+		return err
+	}
+
+	cb.onSuccess()
+	return nil
+}
+
+func (cb *CircuitBreaker) onFailure() {
+	cb.failures++
+	cb.lastFailTime = time.Now()
+
+	if cb.failures >= cb.maxFailures {
+		cb.setState(CircuitOpen)
+		cb.logger.Error("Circuit breaker opened due to failures",
+			"failures", cb.failures,
+			"max_failures", cb.maxFailures)
+	}
+}
+
+func (cb *CircuitBreaker) onSuccess() {
+	cb.failures = 0
+	if cb.state == CircuitHalfOpen {
+		cb.logger.Info("Circuit breaker closed after successful call")
+	}
+	cb.setState(CircuitClosed)
+}
+
+// Retry mechanism with exponential backoff
+type RetryConfig struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+}
+
+// DefaultRetryConfig returns a sensible default retry configuration
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     30 * time.Second,
+		Multiplier:   2.0,
+	}
+}
+
+// PermanentError marks an error that must not be retried
+type PermanentError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so that WithRetry stops immediately
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// WithRetry executes a function with retry logic
+func WithRetry(ctx context.Context, config RetryConfig, logger Logger, fn func() error) error {
+	var lastErr error
+	delay := config.InitialDelay
+
+	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
+		logger.Debug("Attempting operation",
+			"attempt", attempt,
+			"max_attempts", config.MaxAttempts)
+
+		err := fn()
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("Operation succeeded after retry",
+					"successful_attempt", attempt)
+			}
+			return nil
+		}
+
+		lastErr = err
+
+		var permanent *PermanentError
+		if errors.As(err, &permanent) {
+			logger.Warn("Operation failed with non-retryable error",
+				"attempt", attempt,
+				"error", permanent.Err)
+			return permanent.Err
+		}
+
+		if attempt == config.MaxAttempts {
+			logger.Error("All retry attempts exhausted",
+				"attempts", attempt,
+				"last_error", err)
+			break
+		}
+
+		logger.Warn("Operation failed, retrying",
+			"attempt", attempt,
+			"delay", delay,
+			"error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+			// Continue to next attempt
+		}
+
+		// Exponential backoff
+		delay = time.Duration(float64(delay) * config.Multiplier)
+		if delay > config.MaxDelay {
+			delay = config.MaxDelay
+		}
+	}
+
+	return model.NewAppError(model.ErrCodeUnexpected, "Operation failed after all retries", lastErr)
+}
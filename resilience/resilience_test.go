@@ -0,0 +1,194 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"micv/model"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	cb := NewCircuitBreaker(2, 100*time.Millisecond, NopLogger{})
+	ctx := context.Background()
+
+	// Test successful calls
+	if err := cb.Call(ctx, func() error { return nil }); err != nil {
+		t.Errorf("Expected no error for successful call, got: %v", err)
+	}
+
+	// Test failure threshold
+	for i := 0; i < 2; i++ {
+		cb.Call(ctx, func() error { return errors.New("test error") })
+	}
+
+	// Circuit should be open now
+	called := false
+	err := cb.Call(ctx, func() error {
+		called = true
+		return nil
+	})
+	var appErr *model.AppError
+	if !errors.As(err, &appErr) || appErr.Code != model.ErrCodeTimeout {
+		t.Errorf("Expected %s error when open, got: %v", model.ErrCodeTimeout, err)
+	}
+	if called {
+		t.Error("Expected circuit breaker not to call fn when open")
+	}
+
+	// Wait for reset timeout
+	time.Sleep(150 * time.Millisecond)
+
+	// Circuit should allow calls again
+	if err := cb.Call(ctx, func() error { return nil }); err != nil {
+		t.Errorf("Expected circuit breaker to allow call after reset, got: %v", err)
+	}
+}
+
+func TestCircuitBreakerOnStateChange(t *testing.T) {
+	cb := NewCircuitBreaker(1, 50*time.Millisecond, NopLogger{})
+	ctx := context.Background()
+
+	type transition struct{ from, to CircuitState }
+	var transitions []transition
+	cb.OnStateChange(func(from, to CircuitState) {
+		transitions = append(transitions, transition{from, to})
+	})
+
+	// Successes while closed are not transitions
+	cb.Call(ctx, func() error { return nil })
+	cb.Call(ctx, func() error { return errors.New("test error") })
+	time.Sleep(60 * time.Millisecond)
+	cb.Call(ctx, func() error { return nil })
+
+	expected := []transition{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}
+	if !reflect.DeepEqual(transitions, expected) {
+		t.Errorf("Expected transitions %v, got %v", expected, transitions)
+	}
+
+	// A failed trial call reopens the circuit
+	transitions = nil
+	cb.Call(ctx, func() error { return errors.New("test error") })
+	time.Sleep(60 * time.Millisecond)
+	cb.Call(ctx, func() error { return errors.New("test error") })
+
+	expected = []transition{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitOpen},
+	}
+	if !reflect.DeepEqual(transitions, expected) {
+		t.Errorf("Expected transitions %v, got %v", expected, transitions)
+	}
+}
+
+func TestCircuitStateString(t *testing.T) {
+	states := map[CircuitState]string{
+		CircuitClosed:   "closed",
+		CircuitOpen:     "open",
+		CircuitHalfOpen: "half_open",
+		CircuitState(9): "unknown",
+	}
+	for state, expected := range states {
+		if got := state.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
+
+// testRetryConfig retries quickly so tests stay fast
+var testRetryConfig = RetryConfig{
+	MaxAttempts:  3,
+	InitialDelay: 1 * time.Millisecond,
+	MaxDelay:     10 * time.Millisecond,
+	Multiplier:   2.0,
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+
+	// Test successful retry after failures
+	attempts := 0
+	err := WithRetry(ctx, testRetryConfig, NopLogger{}, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("temporary error")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected success after retries, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got: %d", attempts)
+	}
+
+	// Test failure after all retries
+	attempts = 0
+	persistent := errors.New("persistent error")
+	err = WithRetry(ctx, testRetryConfig, NopLogger{}, func() error {
+		attempts++
+		return persistent
+	})
+	var appErr *model.AppError
+	if !errors.As(err, &appErr) || appErr.Code != model.ErrCodeUnexpected || !errors.Is(err, persistent) {
+		t.Errorf("Expected %s error wrapping the last error, got: %v", model.ErrCodeUnexpected, err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got: %d", attempts)
+	}
+}
+
+func TestWithRetryPermanentError(t *testing.T) {
+	rejected := errors.New("token rejected")
+	attempts := 0
+	err := WithRetry(context.Background(), testRetryConfig, NopLogger{}, func() error {
+		attempts++
+		return Permanent(rejected)
+	})
+
+	if err != rejected {
+		t.Errorf("Expected the unwrapped permanent error, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected no retry of a permanent error, got %d attempts", attempts)
+	}
+
+	var permanent *PermanentError
+	if wrapped := Permanent(rejected); !errors.As(wrapped, &permanent) || !errors.Is(wrapped, rejected) || wrapped.Error() != rejected.Error() {
+		t.Errorf("Expected PermanentError to wrap %v, got %v", rejected, wrapped)
+	}
+}
+
+func TestWithRetryContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	config := testRetryConfig
+	config.InitialDelay = time.Hour
+
+	attempts := 0
+	err := WithRetry(ctx, config, NopLogger{}, func() error {
+		attempts++
+		cancel()
+		return errors.New("temporary error")
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context cancellation, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt before cancellation, got %d", attempts)
+	}
+}
+
+func TestDefaultRetryConfig(t *testing.T) {
+	config := DefaultRetryConfig()
+	if config.MaxAttempts != 3 || config.InitialDelay != time.Second || config.MaxDelay != 30*time.Second || config.Multiplier != 2.0 {
+		t.Errorf("Unexpected default retry config: %+v", config)
+	}
+}
@@ -2,7 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
+	"os"
 	"time"
+
+	"micv/client"
+	"micv/trace"
 )
 
 // Dependencies interface defines all external dependencies
@@ -11,6 +16,12 @@ type Dependencies interface {
 	Logger() *Logger
 	Config() *Config
 	CircuitBreaker() *CircuitBreaker
+	TokenCache() *TokenCache
+	Authenticator() Authenticator
+	SecretExtractor() SecretExtractor
+	SubmissionStore() *SubmissionStore
+	Metrics() *Metrics
+	Tracer() *trace.Tracer
 }
 
 // AppDependencies implements Dependencies interface
@@ -19,6 +30,12 @@ type AppDependencies struct {
 	logger         *Logger
 	config         *Config
 	circuitBreaker *CircuitBreaker
+	tokenCache     *TokenCache
+	authenticator  Authenticator
+	extractor      SecretExtractor
+	submissions    *SubmissionStore
+	metrics        *Metrics
+	tracer         *trace.Tracer
 }
 
 // HTTPClient returns the HTTP client
@@ -41,18 +58,109 @@ func (d *AppDependencies) CircuitBreaker() *CircuitBreaker {
 	return d.circuitBreaker
 }
 
+// TokenCache returns the token cache, or nil when caching is disabled
+func (d *AppDependencies) TokenCache() *TokenCache {
+	return d.tokenCache
+}
+
+// Authenticator returns the authenticator for application requests
+func (d *AppDependencies) Authenticator() Authenticator {
+	return d.authenticator
+}
+
+// SecretExtractor returns the extractor for secret endpoint responses
+func (d *AppDependencies) SecretExtractor() SecretExtractor {
+	return d.extractor
+}
+
+// SubmissionStore returns the submission history
+func (d *AppDependencies) SubmissionStore() *SubmissionStore {
+	return d.submissions
+}
+
+// Metrics returns the metrics, or nil when metrics are disabled
+func (d *AppDependencies) Metrics() *Metrics {
+	return d.metrics
+}
+
+// Tracer returns the tracer, or nil when tracing is disabled
+func (d *AppDependencies) Tracer() *trace.Tracer {
+	return d.tracer
+}
+
+// WithHTTPClient replaces the HTTP client, typically with a decorated one
+func (d *AppDependencies) WithHTTPClient(client HTTPClient) *AppDependencies {
+	d.httpClient = client
+	return d
+}
+
 // NewAppDependencies creates a new dependencies container
-func NewAppDependencies(config *Config, logLevel LogLevel) *AppDependencies {
+func NewAppDependencies(config *Config, logLevel LogLevel) (*AppDependencies, error) {
 	logger := NewLogger(logLevel)
-	httpClient := NewHTTPClientWithTimeout(time.Duration(config.Timeout) * time.Second)
+	httpClient, err := NewHTTPClientFromConfig(config)
+	if err != nil {
+		return nil, WrapConfigError(err, "tls")
+	}
+	if len(config.RateLimits) > 0 {
+		httpClient = NewRateLimitedClient(httpClient, config.RateLimits)
+	}
+	metrics := NewMetrics(config.Metrics, logger)
 	circuitBreaker := NewCircuitBreaker(3, 30*time.Second, logger)
+	if metrics != nil {
+		circuitBreaker.OnStateChange(metrics.CircuitStateChanged)
+	}
+	tokenCache, err := newTokenCacheFromConfig(config)
+	if err != nil {
+		return nil, WrapConfigError(err, "token_cache_file")
+	}
+	authenticator, err := NewAuthenticator(config.Auth)
+	if err != nil {
+		return nil, WrapConfigError(err, "auth")
+	}
+	extractor, err := NewSecretExtractor(config.SecretResponse)
+	if err != nil {
+		return nil, WrapConfigError(err, "secret_response")
+	}
+	submissions, err := newSubmissionStoreFromConfig(config)
+	if err != nil {
+		return nil, WrapConfigError(err, "submission_store_file")
+	}
 
 	return &AppDependencies{
 		httpClient:     httpClient,
 		logger:         logger,
 		config:         config,
 		circuitBreaker: circuitBreaker,
+		tokenCache:     tokenCache,
+		authenticator:  authenticator,
+		extractor:      extractor,
+		submissions:    submissions,
+		metrics:        metrics,
+		tracer:         newTracerFromConfig(config.Tracing),
+	}, nil
+}
+
+// newClient creates a library client for the configured endpoints that shares
+// the HTTP client, authenticator, extractor and circuit breaker of deps
+func newClient(deps Dependencies, logger *Logger) *client.Client {
+	config := deps.Config()
+	opts := []client.Option{
+		client.WithHTTPClient(deps.HTTPClient()),
+		client.WithAuthenticator(deps.Authenticator()),
+		client.WithSecretExtractor(deps.SecretExtractor()),
+		client.WithRetry(config.RetryConfig()),
+		client.WithCircuitBreaker(deps.CircuitBreaker()),
+		client.WithLogger(logger),
+		client.WithOutput(os.Stdout),
+		client.WithAttachmentOptions(config.Attachments.options()),
+	}
+	if metrics := deps.Metrics(); metrics != nil {
+		opts = append(opts, client.WithObserver(metrics))
+	}
+	if config.Hooks.configured() {
+		opts = append(opts, client.WithHooks(NewCommandHook(config.Hooks, logger)))
 	}
+	return client.New(config.SecretURL, config.ApplicationURL, opts...)
 }
 
 // ApplicationService provides high-level application operations
@@ -69,6 +177,17 @@ func NewApplicationService(deps Dependencies) *ApplicationService {
 
 // SubmitApplication handles the complete application submission process
 func (s *ApplicationService) SubmitApplication(ctx context.Context, appData ApplicationData) error {
+	_, err := s.submitApplication(ctx, appData)
+	return err
+}
+
+// submitApplication submits the application and returns the final response, if any
+func (s *ApplicationService) submitApplication(ctx context.Context, appData ApplicationData) (resp *SubmitResponse, err error) {
+	ctx, span := s.deps.Tracer().Start(ctx, "ApplicationService.SubmitApplication")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
 	logger := s.deps.Logger().With("operation", "submit_application")
 
 	logger.Debug("Starting application submission",
@@ -77,99 +196,182 @@ func (s *ApplicationService) SubmitApplication(ctx context.Context, appData Appl
 		"job_title", appData.JobTitle)
 
 	// Validate application data
-	if err := s.validateApplication(appData); err != nil {
+	if err := s.validateApplication(ctx, appData); err != nil {
 		logger.Error("Application validation failed", "error", err)
-		return WrapValidationError(err, "application_data")
+		return nil, WrapValidationError(err, "application_data")
 	}
 
-	// Fetch authorization token with circuit breaker protection
-	token, err := s.fetchTokenWithResilience(ctx)
+	// Refuse to resubmit an identical payload that already succeeded
+	idempotencyKey, err := IdempotencyKey(s.deps.Config().ApplicationURL, appData)
+	if err != nil {
+		return nil, NewAppError(ErrCodeParsing, "Failed to compute idempotency key", err)
+	}
+	previous, ok, err := s.deps.SubmissionStore().Get(idempotencyKey)
+	if err != nil {
+		// Without the history a duplicate cannot be ruled out
+		if !s.deps.Config().Force {
+			logger.Error("Failed to read submission history", "error", err)
+			return nil, NewAppError(ErrCodeConfig, "Failed to read submission history", err).
+				WithContext("hint", "fix or remove the submission history file, or use --force to submit anyway")
+		}
+		logger.Warn("Submitting without checking the submission history (forced)", "error", err)
+	}
+	if ok && previous.Succeeded() {
+		if !s.deps.Config().Force {
+			logger.Warn("Identical application already submitted", "idempotency_key", idempotencyKey)
+			return nil, NewAppError(ErrCodeApplication, "Identical application was already submitted successfully", nil).
+				WithContext("idempotency_key", idempotencyKey).
+				WithContext("submitted_at", previous.SubmittedAt.Format(time.RFC3339)).
+				WithContext("hint", "use --force to submit again")
+		}
+		logger.Info("Resubmitting identical application (forced)", "idempotency_key", idempotencyKey)
+	}
+
+	// Obtain authorization token from static configuration, cache or secret endpoint
+	token, cached, err := s.acquireToken(ctx, logger)
 	if err != nil {
 		logger.Error("Failed to fetch authorization token", "error", err)
-		return err
+		return nil, err
 	}
+	span.SetAttribute("token.cached", cached)
 
 	// Submit application with retry mechanism
-	if err := s.submitWithResilience(ctx, token, appData); err != nil {
+	resp, err = s.submitWithResilience(ctx, token, cached, appData, idempotencyKey)
+	if errors.Is(err, ErrTokenRejected) {
+		s.invalidateCachedToken(logger)
+
+		// Only a rejected cached token is an error; otherwise the rejection is
+		// reported like any other non-success status
+		if !cached {
+			err = nil
+		}
+	}
+	if errors.Is(err, client.ErrSubmissionVetoed) {
+		// Nothing was sent, so there is nothing to record
+		logger.Error("Submission vetoed by hook", "error", err)
+		return nil, err
+	}
+	s.recordSubmission(logger, idempotencyKey, appData, resp, err)
+	if err != nil {
 		logger.Error("Failed to submit application", "error", err)
-		return err
+		return resp, err
 	}
 
 	logger.Debug("Application submitted successfully")
-	return nil
+	return resp, nil
 }
 
-// validateApplication validates the application data
-func (s *ApplicationService) validateApplication(appData ApplicationData) error {
-	result := validateApplicationDataFunctional(appData)
-	if result.IsError() {
-		return result.Error
+// recordSubmission stores the outcome of a submission in the submission history
+func (s *ApplicationService) recordSubmission(logger *Logger, idempotencyKey string, appData ApplicationData, resp *SubmitResponse, submitErr error) {
+	payload, err := canonicalPayload(appData)
+	if err != nil {
+		logger.Warn("Failed to encode payload for submission history", "error", err)
+		return
+	}
+
+	record := SubmissionRecord{
+		IdempotencyKey: idempotencyKey,
+		URL:            s.deps.Config().ApplicationURL,
+		Outcome:        SubmissionFailed,
+		Payload:        payload,
+		SubmittedAt:    time.Now(),
+	}
+	if resp != nil {
+		record.StatusCode = resp.StatusCode
+	}
+	if submitErr != nil {
+		record.Error = submitErr.Error()
+	} else if resp.Succeeded() {
+		record.Outcome = SubmissionSucceeded
+	}
+
+	if err := s.deps.SubmissionStore().Put(record); err != nil {
+		logger.Warn("Failed to record submission", "error", err)
 	}
-	return nil
 }
 
-// fetchTokenWithResilience fetches auth token with circuit breaker protection
-func (s *ApplicationService) fetchTokenWithResilience(ctx context.Context) (string, error) {
-	logger := s.deps.Logger().With("operation", "fetch_token")
+// validateApplication validates the application data
+func (s *ApplicationService) validateApplication(ctx context.Context, appData ApplicationData) error {
+	_, span := s.deps.Tracer().Start(ctx, "ApplicationService.validateApplication")
+	defer span.End()
 
-	var token string
-	var err error
+	err := newClient(s.deps, s.deps.Logger()).Validate(appData)
+	span.RecordError(err)
+	return err
+}
 
-	circuitBreakerErr := s.deps.CircuitBreaker().Call(ctx, func() error {
-		token, err = s.fetchTokenWithRetry(ctx)
-		return err
-	})
+// acquireToken returns a static token, a cached token, or a freshly fetched one.
+// cached reports whether the token came from the token cache.
+func (s *ApplicationService) acquireToken(ctx context.Context, logger *Logger) (token string, cached bool, err error) {
+	if token, ok := s.deps.Authenticator().StaticToken(); ok {
+		logger.Debug("Using static authorization token")
+		return token, false, nil
+	}
 
-	if circuitBreakerErr != nil {
-		logger.Error("Circuit breaker rejected token fetch", "error", circuitBreakerErr)
-		return "", circuitBreakerErr
+	if token, ok := s.deps.TokenCache().Get(s.deps.Config().SecretURL); ok {
+		logger.Debug("Using cached authorization token")
+		return token, true, nil
 	}
 
-	return token, nil
+	token, err = s.fetchTokenWithResilience(ctx)
+	return token, false, err
 }
 
-// fetchTokenWithRetry fetches auth token with retry logic
-func (s *ApplicationService) fetchTokenWithRetry(ctx context.Context) (string, error) {
-	logger := s.deps.Logger().With("operation", "fetch_token_retry")
-
-	var token string
-
-	err := WithRetry(ctx, DefaultRetryConfig(), logger, func() error {
-		var fetchErr error
-		token, fetchErr = getAuthTokenWithClient(s.deps.HTTPClient(), s.deps.Config().SecretURL)
-		if fetchErr != nil {
-			logger.Debug("Token fetch attempt failed", "error", fetchErr)
-			return WrapAuthError(fetchErr, s.deps.Config().SecretURL)
-		}
-		return nil
-	})
+// fetchTokenWithResilience fetches auth token with retries behind the circuit breaker
+func (s *ApplicationService) fetchTokenWithResilience(ctx context.Context) (string, error) {
+	ctx, span := s.deps.Tracer().Start(ctx, "ApplicationService.fetchTokenWithResilience")
+	defer span.End()
+	logger := s.deps.Logger().With("operation", "fetch_token")
 
+	token, err := newClient(s.deps, logger).FetchToken(ctx)
 	if err != nil {
+		span.RecordError(err)
+		logger.Error("Token fetch failed", "error", err)
 		return "", err
 	}
 
+	if err := s.deps.TokenCache().Put(s.deps.Config().SecretURL, token); err != nil {
+		logger.Warn("Failed to cache authorization token", "error", err)
+	}
+
 	return token, nil
 }
 
-// submitWithResilience submits application with retry mechanism
-func (s *ApplicationService) submitWithResilience(ctx context.Context, token string, appData ApplicationData) error {
-	logger := s.deps.Logger().With("operation", "submit_with_resilience")
+// invalidateCachedToken removes a rejected token from the token cache
+func (s *ApplicationService) invalidateCachedToken(logger *Logger) {
+	if err := s.deps.TokenCache().Invalidate(s.deps.Config().SecretURL); err != nil {
+		logger.Warn("Failed to invalidate cached token", "error", err)
+	}
+}
 
-	return WithRetry(ctx, DefaultRetryConfig(), logger, func() error {
-		err := submitApplicationWithClient(
-			s.deps.HTTPClient(),
-			s.deps.Config().ApplicationURL,
-			token,
-			appData,
-		)
-
-		if err != nil {
-			logger.Debug("Application submission attempt failed", "error", err)
-			return WrapNetworkError(err, s.deps.Config().ApplicationURL)
-		}
+// submitWithResilience submits application with retry mechanism, sending the same
+// idempotency key on every attempt. A rejected cached token may simply be stale,
+// so it is replaced with a fresh one and the submission retried once; hooks
+// still run only once.
+func (s *ApplicationService) submitWithResilience(ctx context.Context, token string, cached bool, appData ApplicationData, idempotencyKey string) (*SubmitResponse, error) {
+	ctx, span := s.deps.Tracer().Start(ctx, "ApplicationService.submitWithResilience")
+	span.SetAttribute("idempotency_key", idempotencyKey)
+	defer span.End()
 
-		return nil
-	})
+	logger := s.deps.Logger().With("operation", "submit_with_resilience")
+	opts := []client.SubmitOption{client.WithIdempotencyKey(idempotencyKey)}
+	if cached {
+		opts = append(opts, client.WithTokenRefresh(func(ctx context.Context) (string, error) {
+			logger.Info("Cached authorization token rejected, fetching a fresh token")
+			s.invalidateCachedToken(logger)
+			token, err := s.fetchTokenWithResilience(ctx)
+			if err != nil {
+				logger.Error("Failed to fetch authorization token", "error", err)
+			}
+			return token, err
+		}))
+	}
+	resp, err := newClient(s.deps, logger).Submit(ctx, token, appData, opts...)
+	span.RecordError(err)
+	if resp != nil {
+		span.SetAttribute("http.status_code", resp.StatusCode)
+	}
+	return resp, err
 }
 
 // AuthTokenService handles token-related operations
@@ -188,15 +390,29 @@ func NewAuthTokenService(deps Dependencies) *AuthTokenService {
 func (s *AuthTokenService) GetToken(ctx context.Context) (string, error) {
 	logger := s.deps.Logger().With("service", "auth_token")
 
+	if token, ok := s.deps.Authenticator().StaticToken(); ok {
+		logger.Debug("Using static authentication token")
+		return token, nil
+	}
+
+	if token, ok := s.deps.TokenCache().Get(s.deps.Config().SecretURL); ok {
+		logger.Debug("Using cached authentication token")
+		return token, nil
+	}
+
 	logger.Debug("Fetching authentication token",
 		"endpoint", s.deps.Config().SecretURL)
 
-	token, err := getAuthTokenWithClient(s.deps.HTTPClient(), s.deps.Config().SecretURL)
+	token, err := newClient(s.deps, logger).FetchTokenOnce()
 	if err != nil {
 		logger.Error("Failed to fetch token", "error", err)
 		return "", WrapAuthError(err, s.deps.Config().SecretURL)
 	}
 
+	if err := s.deps.TokenCache().Put(s.deps.Config().SecretURL, token); err != nil {
+		logger.Warn("Failed to cache authentication token", "error", err)
+	}
+
 	logger.Debug("Authentication token fetched successfully")
 	return token, nil
 }
@@ -213,30 +429,24 @@ func NewConfigService(deps Dependencies) *ConfigService {
 	}
 }
 
-// ValidateConfig validates the current configuration
+// ValidateConfig validates the current configuration with the same rules as
+// the validate command and logs warnings for risky settings
 func (s *ConfigService) ValidateConfig() error {
 	logger := s.deps.Logger().With("service", "config")
 	config := s.deps.Config()
 
-	if config.SecretURL == "" {
-		return WrapConfigError(
-			NewAppError(ErrCodeConfig, "secret URL is required", nil),
-			"secret_url",
-		)
+	if err := ValidateConfig(config); err != nil {
+		return NewAppError(ErrCodeConfig, "Invalid configuration", err)
 	}
 
-	if config.ApplicationURL == "" {
-		return WrapConfigError(
-			NewAppError(ErrCodeConfig, "application URL is required", nil),
-			"application_url",
-		)
+	if endpointHostsDiffer(config) {
+		logger.Warn("Secret and application URLs point at different hosts",
+			"secret_url", config.SecretURL,
+			"application_url", config.ApplicationURL)
 	}
 
-	if config.Timeout <= 0 {
-		return WrapConfigError(
-			NewAppError(ErrCodeConfig, "timeout must be positive", nil),
-			"timeout",
-		)
+	if config.TLS.InsecureSkipVerify {
+		logger.Warn("TLS certificate verification is disabled")
 	}
 
 	logger.Debug("Configuration validation successful")
@@ -249,34 +459,55 @@ type Application struct {
 	appService    *ApplicationService
 	authService   *AuthTokenService
 	configService *ConfigService
+	notifier      *Notifier
 }
 
 // NewApplication creates a new application instance
 func NewApplication(deps Dependencies) *Application {
+	config := deps.Config()
 	return &Application{
 		deps:          deps,
 		appService:    NewApplicationService(deps),
 		authService:   NewAuthTokenService(deps),
 		configService: NewConfigService(deps),
+		notifier:      NewNotifier(config.Notifications, config.RetryConfig(), deps.Logger(), deps.Metrics()),
 	}
 }
 
-// Run executes the main application logic
+// Run executes the main application logic and notifies the configured webhooks of the outcome
 func (app *Application) Run(ctx context.Context, appData ApplicationData) error {
-	logger := app.deps.Logger().With("component", "application")
+	runID := newRunID()
+	logger := app.deps.Logger().With("component", "application", "run_id", runID)
+
+	ctx, span := app.deps.Tracer().Start(ctx, "Application.Run")
+	span.SetAttribute("run_id", runID)
+	defer span.End()
 
+	resp, err := app.run(ctx, logger, appData)
+	span.RecordError(err)
+	app.notifier.Notify(ctx, newNotification(runID, app.deps.Config().ApplicationURL, appData, resp, err))
+	return err
+}
+
+// run validates the configuration and submits the application
+func (app *Application) run(ctx context.Context, logger *Logger, appData ApplicationData) (*SubmitResponse, error) {
 	// Validate configuration
-	if err := app.configService.ValidateConfig(); err != nil {
+	_, span := trace.Start(ctx, "ConfigService.ValidateConfig")
+	err := app.configService.ValidateConfig()
+	span.RecordError(err)
+	span.End()
+	if err != nil {
 		logger.Error("Configuration validation failed", "error", err)
-		return err
+		return nil, err
 	}
 
 	// Submit application
-	if err := app.appService.SubmitApplication(ctx, appData); err != nil {
+	resp, err := app.appService.submitApplication(ctx, appData)
+	if err != nil {
 		logger.Error("Application submission failed", "error", err)
-		return err
+		return resp, err
 	}
 
 	logger.Debug("Application execution completed successfully")
-	return nil
+	return resp, nil
 }
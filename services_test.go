@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"testing"
 	"time"
+
+	"micv/trace"
 )
 
 // TestApplicationService tests the application service
@@ -96,94 +98,6 @@ func TestApplicationService(t *testing.T) {
 	}
 }
 
-// TestCircuitBreaker tests the circuit breaker functionality
-func TestCircuitBreaker(t *testing.T) {
-	logger := NewLogger(LogLevelError) // Reduce log noise during tests
-	cb := NewCircuitBreaker(2, 100*time.Millisecond, logger)
-
-	ctx := context.Background()
-
-	// Test successful calls
-	err := cb.Call(ctx, func() error {
-		return nil
-	})
-	if err != nil {
-		t.Errorf("Expected no error for successful call, got: %v", err)
-	}
-
-	// Test failure threshold
-	for i := 0; i < 2; i++ {
-		cb.Call(ctx, func() error {
-			return errors.New("test error")
-		})
-	}
-
-	// Circuit should be open now
-	err = cb.Call(ctx, func() error {
-		return nil
-	})
-	if err == nil {
-		t.Error("Expected circuit breaker to reject call when open")
-	}
-
-	// Wait for reset timeout
-	time.Sleep(150 * time.Millisecond)
-
-	// Circuit should allow calls again
-	err = cb.Call(ctx, func() error {
-		return nil
-	})
-	if err != nil {
-		t.Errorf("Expected circuit breaker to allow call after reset, got: %v", err)
-	}
-}
-
-// TestRetryMechanism tests the retry functionality
-func TestRetryMechanism(t *testing.T) {
-	logger := NewLogger(LogLevelError) // Reduce log noise during tests
-	ctx := context.Background()
-
-	attempts := 0
-	config := RetryConfig{
-		MaxAttempts:  3,
-		InitialDelay: 1 * time.Millisecond,
-		MaxDelay:     10 * time.Millisecond,
-		Multiplier:   2.0,
-	}
-
-	// Test successful retry after failures
-	err := WithRetry(ctx, config, logger, func() error {
-		attempts++
-		if attempts < 3 {
-			return errors.New("temporary error")
-		}
-		return nil
-	})
-
-	if err != nil {
-		t.Errorf("Expected success after retries, got: %v", err)
-	}
-
-	if attempts != 3 {
-		t.Errorf("Expected 3 attempts, got: %d", attempts)
-	}
-
-	// Test failure after all retries
-	attempts = 0
-	err = WithRetry(ctx, config, logger, func() error {
-		attempts++
-		return errors.New("persistent error")
-	})
-
-	if err == nil {
-		t.Error("Expected error after all retries exhausted")
-	}
-
-	if attempts != 3 {
-		t.Errorf("Expected 3 attempts, got: %d", attempts)
-	}
-}
-
 // TestFunctionalValidation tests the functional validation
 func TestFunctionalValidation(t *testing.T) {
 	tests := []struct {
@@ -250,6 +164,12 @@ type MockDependencies struct {
 	logger         *Logger
 	config         *Config
 	circuitBreaker *CircuitBreaker
+	tokenCache     *TokenCache
+	authenticator  Authenticator
+	extractor      SecretExtractor
+	submissions    *SubmissionStore
+	metrics        *Metrics
+	tracer         *trace.Tracer
 }
 
 func NewMockDependencies() *MockDependencies {
@@ -261,6 +181,8 @@ func NewMockDependencies() *MockDependencies {
 		logger:         logger,
 		config:         config,
 		circuitBreaker: NewCircuitBreaker(3, 30*time.Second, logger),
+		authenticator:  RawTokenAuthenticator{},
+		extractor:      JSONSecretExtractor{},
 	}
 }
 
@@ -280,6 +202,30 @@ func (m *MockDependencies) CircuitBreaker() *CircuitBreaker {
 	return m.circuitBreaker
 }
 
+func (m *MockDependencies) TokenCache() *TokenCache {
+	return m.tokenCache
+}
+
+func (m *MockDependencies) Authenticator() Authenticator {
+	return m.authenticator
+}
+
+func (m *MockDependencies) SecretExtractor() SecretExtractor {
+	return m.extractor
+}
+
+func (m *MockDependencies) SubmissionStore() *SubmissionStore {
+	return m.submissions
+}
+
+func (m *MockDependencies) Metrics() *Metrics {
+	return m.metrics
+}
+
+func (m *MockDependencies) Tracer() *trace.Tracer {
+	return m.tracer
+}
+
 // TestApplication tests the main application flow
 func TestApplication(t *testing.T) {
 	deps := NewMockDependencies()
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Submission outcomes stored in the submission history
+const (
+	SubmissionSucceeded = "succeeded"
+	SubmissionFailed    = "failed"
+)
+
+// SubmissionRecord is the stored outcome of a submission with a given idempotency key
+type SubmissionRecord struct {
+	IdempotencyKey string          `json:"idempotency_key"`
+	URL            string          `json:"url"`
+	Outcome        string          `json:"outcome"`
+	StatusCode     int             `json:"status_code,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	SubmittedAt    time.Time       `json:"submitted_at"`
+
+	// LastAttempt is a failed attempt made after the recorded success
+	LastAttempt *SubmissionAttempt `json:"last_attempt,omitempty"`
+}
+
+// SubmissionAttempt is the outcome of a single later submission attempt
+type SubmissionAttempt struct {
+	Outcome     string    `json:"outcome"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// Succeeded reports whether the recorded submission succeeded
+func (r SubmissionRecord) Succeeded() bool {
+	return r.Outcome == SubmissionSucceeded
+}
+
+// Email returns the applicant email of the recorded payload
+func (r SubmissionRecord) Email() string {
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(r.Payload, &payload); err != nil {
+		return ""
+	}
+	return payload.Email
+}
+
+// IdempotencyKey derives a deterministic key from the target URL and normalized payload
+func IdempotencyKey(applicationURL string, appData ApplicationData) (string, error) {
+	payload, err := canonicalPayload(appData)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(applicationURL))
+	hash.Write([]byte{'\n'})
+	hash.Write(payload)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// canonicalPayload encodes application data as compact JSON with sorted object
+// keys. Attachments are identified by their field and a SHA-256 digest of their
+// content, so replacing a file changes the payload and local paths are left out.
+func canonicalPayload(appData ApplicationData) ([]byte, error) {
+	digests, err := attachmentDigests(appData.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	appData.Attachments = nil
+
+	data, err := json.Marshal(appData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	// Round-trip through a generic value so every object's keys are sorted
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to normalize payload: %w", err)
+	}
+	if len(digests) > 0 {
+		generic["attachments"] = digests
+	}
+	return json.Marshal(generic)
+}
+
+// attachmentDigest identifies an attachment in the canonical payload
+type attachmentDigest struct {
+	Field  string `json:"field"`
+	SHA256 string `json:"sha256"`
+}
+
+// attachmentDigests hashes the content of each attachment
+func attachmentDigests(attachments []Attachment) ([]attachmentDigest, error) {
+	digests := make([]attachmentDigest, 0, len(attachments))
+	for _, attachment := range attachments {
+		file, err := os.Open(attachment.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attachment %s: %w", attachment.Field, err)
+		}
+
+		hash := sha256.New()
+		_, err = io.Copy(hash, file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attachment %s: %w", attachment.Field, err)
+		}
+
+		digests = append(digests, attachmentDigest{Field: attachment.Field, SHA256: hex.EncodeToString(hash.Sum(nil))})
+	}
+	return digests, nil
+}
+
+// SubmissionStore persists submission outcomes keyed by idempotency key.
+// A nil *SubmissionStore is valid and records nothing.
+type SubmissionStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultSubmissionStorePath returns the submission history file under the user cache directory
+func DefaultSubmissionStorePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "micv", "submissions.json"), nil
+}
+
+// NewSubmissionStore creates a submission store backed by path
+func NewSubmissionStore(path string) *SubmissionStore {
+	return &SubmissionStore{path: path}
+}
+
+// newSubmissionStoreFromConfig creates the submission store at the configured or default path
+func newSubmissionStoreFromConfig(config *Config) (*SubmissionStore, error) {
+	path := config.SubmissionStoreFile
+	if path == "" {
+		defaultPath, err := DefaultSubmissionStorePath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	return NewSubmissionStore(path), nil
+}
+
+// Get returns the record stored for key. An unreadable history is reported
+// as an error rather than as a missing record.
+func (s *SubmissionStore) Get(key string) (SubmissionRecord, bool, error) {
+	if s == nil {
+		return SubmissionRecord{}, false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return SubmissionRecord{}, false, err
+	}
+
+	record, ok := records[key]
+	return record, ok, nil
+}
+
+// Put stores record under its idempotency key. A failed record never replaces
+// a successful one; it is kept as the last attempt of the success instead.
+func (s *SubmissionStore) Put(record SubmissionRecord) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if existing, ok := records[record.IdempotencyKey]; ok && existing.Succeeded() && !record.Succeeded() {
+		existing.LastAttempt = &SubmissionAttempt{
+			Outcome:     record.Outcome,
+			StatusCode:  record.StatusCode,
+			Error:       record.Error,
+			SubmittedAt: record.SubmittedAt,
+		}
+		record = existing
+	}
+
+	records[record.IdempotencyKey] = record
+	return s.save(records)
+}
+
+// LatestSucceeded returns the most recent successful submission to url by the
+// applicant with the given email
+func (s *SubmissionStore) LatestSucceeded(url, email string) (SubmissionRecord, bool, error) {
+	if s == nil {
+		return SubmissionRecord{}, false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return SubmissionRecord{}, false, err
+	}
+
+	var latest SubmissionRecord
+	found := false
+	for _, record := range records {
+		if record.URL != url || !record.Succeeded() || !strings.EqualFold(record.Email(), email) {
+			continue
+		}
+		if !found || record.SubmittedAt.After(latest.SubmittedAt) {
+			latest = record
+			found = true
+		}
+	}
+	return latest, found, nil
+}
+
+// load reads the store file, treating a missing file as empty
+func (s *SubmissionStore) load() (map[string]SubmissionRecord, error) {
+	records := make(map[string]SubmissionRecord)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read submission history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode submission history: %w", err)
+	}
+	return records, nil
+}
+
+// save writes the store file with owner-only permissions
+func (s *SubmissionStore) save(records map[string]SubmissionRecord) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create submission history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode submission history: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write submission history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	first := ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+		ExtraInformation: map[string]interface{}{
+			"location":     "Australia",
+			"availability": "Immediate",
+		},
+	}
+	// Same content with extra information built in a different order
+	second := first
+	second.ExtraInformation = map[string]interface{}{
+		"availability": "Immediate",
+		"location":     "Australia",
+	}
+
+	key1, err := IdempotencyKey("https://example.com/apply", first)
+	if err != nil {
+		t.Fatalf("IdempotencyKey failed: %v", err)
+	}
+	key2, _ := IdempotencyKey("https://example.com/apply", second)
+	if key1 != key2 {
+		t.Error("Expected identical payloads to produce the same key")
+	}
+
+	key3, _ := IdempotencyKey("https://other.example.com/apply", first)
+	if key1 == key3 {
+		t.Error("Expected different target URLs to produce different keys")
+	}
+
+	changed := first
+	changed.JobTitle = "Backend Engineer"
+	key4, _ := IdempotencyKey("https://example.com/apply", changed)
+	if key1 == key4 {
+		t.Error("Expected different payloads to produce different keys")
+	}
+}
+
+func TestIdempotencyKeyHashesAttachmentContent(t *testing.T) {
+	dir := t.TempDir()
+	resume := filepath.Join(dir, "resume.pdf")
+	writeFile := func(content string) {
+		if err := os.WriteFile(resume, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write attachment: %v", err)
+		}
+	}
+	appData := ApplicationData{
+		Name:        "John Doe",
+		Email:       "john@example.com",
+		JobTitle:    "Software Engineer",
+		Attachments: []Attachment{{Field: "resume", Path: resume}},
+	}
+
+	writeFile("%PDF-1.4 first version")
+	key1, err := IdempotencyKey("https://example.com/apply", appData)
+	if err != nil {
+		t.Fatalf("IdempotencyKey failed: %v", err)
+	}
+
+	// The same content at another path keeps the key
+	moved := filepath.Join(dir, "moved.pdf")
+	if err := os.WriteFile(moved, []byte("%PDF-1.4 first version"), 0644); err != nil {
+		t.Fatalf("Failed to write attachment: %v", err)
+	}
+	relocated := appData
+	relocated.Attachments = []Attachment{{Field: "resume", Path: moved}}
+	if key, _ := IdempotencyKey("https://example.com/apply", relocated); key != key1 {
+		t.Error("Expected the attachment path not to affect the key")
+	}
+
+	// Replacing the file in place changes the key
+	writeFile("%PDF-1.4 second version")
+	if key, _ := IdempotencyKey("https://example.com/apply", appData); key == key1 {
+		t.Error("Expected new attachment content to produce a different key")
+	}
+
+	payload, err := canonicalPayload(appData)
+	if err != nil {
+		t.Fatalf("canonicalPayload failed: %v", err)
+	}
+	if strings.Contains(string(payload), dir) || !strings.Contains(string(payload), `"sha256"`) {
+		t.Errorf("Expected payload with digests and no local paths, got %s", payload)
+	}
+
+	os.Remove(resume)
+	if _, err := IdempotencyKey("https://example.com/apply", appData); err == nil {
+		t.Error("Expected error for missing attachment")
+	}
+}
+
+func TestIdempotentSubmission(t *testing.T) {
+	appData := ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	}
+
+	deps := NewMockDependencies()
+	deps.submissions = NewSubmissionStore(filepath.Join(t.TempDir(), "submissions.json"))
+
+	expectedKey, _ := IdempotencyKey(deps.config.ApplicationURL, appData)
+	var keys []string
+	failures := 1
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		keys = append(keys, req.Header.Get("Idempotency-Key"))
+		if failures > 0 {
+			failures--
+			return nil, http.ErrHandlerTimeout
+		}
+		return createResponse(200, `{"status":"success"}`), nil
+	}
+
+	service := NewApplicationService(deps)
+	if err := service.SubmitApplication(context.Background(), appData); err != nil {
+		t.Fatalf("Expected submission to succeed after retry, got: %v", err)
+	}
+
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(keys))
+	}
+	for i, key := range keys {
+		if key != expectedKey {
+			t.Errorf("Attempt %d: expected Idempotency-Key %s, got %s", i+1, expectedKey, key)
+		}
+	}
+
+	record, ok, _ := deps.submissions.Get(expectedKey)
+	if !ok || !record.Succeeded() || record.StatusCode != 200 {
+		t.Fatalf("Expected succeeded record, got %+v (found=%v)", record, ok)
+	}
+
+	// Identical payload is refused
+	err := service.SubmitApplication(context.Background(), appData)
+	appErr, ok := err.(*AppError)
+	if !ok || appErr.Code != ErrCodeApplication {
+		t.Fatalf("Expected %s error for duplicate submission, got: %v", ErrCodeApplication, err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("Expected no request for refused duplicate, got %d total", len(keys))
+	}
+
+	// --force overrides the refusal
+	deps.config.Force = true
+	if err := service.SubmitApplication(context.Background(), appData); err != nil {
+		t.Errorf("Expected forced resubmission to succeed, got: %v", err)
+	}
+	if len(keys) != 3 {
+		t.Errorf("Expected forced resubmission to send a request, got %d total", len(keys))
+	}
+}
+
+func TestFailedSubmissionDoesNotBlockRetry(t *testing.T) {
+	appData := ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	}
+
+	deps := NewMockDependencies()
+	deps.submissions = NewSubmissionStore(filepath.Join(t.TempDir(), "submissions.json"))
+	status := 500
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return createResponse(status, `{}`), nil
+	}
+
+	service := NewApplicationService(deps)
+	service.SubmitApplication(context.Background(), appData)
+
+	key, _ := IdempotencyKey(deps.config.ApplicationURL, appData)
+	if record, ok, _ := deps.submissions.Get(key); !ok || record.Succeeded() {
+		t.Fatalf("Expected failed record, got %+v (found=%v)", record, ok)
+	}
+
+	status = 200
+	if err := service.SubmitApplication(context.Background(), appData); err != nil {
+		t.Errorf("Expected resubmission after failure to be allowed, got: %v", err)
+	}
+}
+
+func TestFailedForcedResubmissionKeepsSuccess(t *testing.T) {
+	appData := ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	}
+
+	deps := NewMockDependencies()
+	deps.submissions = NewSubmissionStore(filepath.Join(t.TempDir(), "submissions.json"))
+	status := 200
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return createResponse(status, `{}`), nil
+	}
+
+	service := NewApplicationService(deps)
+	if err := service.SubmitApplication(context.Background(), appData); err != nil {
+		t.Fatalf("Expected submission to succeed, got: %v", err)
+	}
+
+	status = 500
+	deps.config.Force = true
+	service.SubmitApplication(context.Background(), appData)
+
+	key, _ := IdempotencyKey(deps.config.ApplicationURL, appData)
+	record, ok, _ := deps.submissions.Get(key)
+	if !ok || !record.Succeeded() || record.StatusCode != 200 {
+		t.Fatalf("Expected the success to be kept, got %+v (found=%v)", record, ok)
+	}
+	if record.LastAttempt == nil || record.LastAttempt.Outcome != SubmissionFailed || record.LastAttempt.StatusCode != 500 {
+		t.Errorf("Expected the failed attempt to be recorded, got %+v", record.LastAttempt)
+	}
+
+	// The duplicate guard still applies
+	deps.config.Force = false
+	if err := service.SubmitApplication(context.Background(), appData); err == nil {
+		t.Error("Expected the duplicate to be refused after a failed forced resubmission")
+	}
+}
+
+func TestUnreadableSubmissionHistory(t *testing.T) {
+	appData := ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	}
+
+	path := filepath.Join(t.TempDir(), "submissions.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	deps := NewMockDependencies()
+	deps.submissions = NewSubmissionStore(path)
+	submits := 0
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		submits++
+		return createResponse(200, `{}`), nil
+	}
+
+	if _, _, err := deps.submissions.Get("key"); err == nil {
+		t.Error("Expected Get to report the unreadable history")
+	}
+
+	service := NewApplicationService(deps)
+	err := service.SubmitApplication(context.Background(), appData)
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeConfig {
+		t.Errorf("Expected %s error for an unreadable history, got %v", ErrCodeConfig, err)
+	}
+	if submits != 0 {
+		t.Errorf("Expected no request without a readable history, got %d", submits)
+	}
+
+	deps.config.Force = true
+	if err := service.SubmitApplication(context.Background(), appData); err != nil {
+		t.Errorf("Expected forced submission to proceed, got: %v", err)
+	}
+	if submits != 1 {
+		t.Errorf("Expected forced submission to send a request, got %d", submits)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// templateMarker identifies string values that contain template actions
+const templateMarker = "{{"
+
+// TemplateData is the data available to templates in application data files
+type TemplateData struct {
+	Name         string
+	Email        string
+	JobTitle     string
+	FinalAttempt bool
+	Vars         map[string]string
+}
+
+// parseTemplateVars parses repeated key=value pairs; later values win
+func parseTemplateVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (expected key=value)", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// RenderApplicationData renders the text/template actions in the string values
+// of application data. The name, email and job title are rendered first and
+// are then available to the other fields as .Name, .Email and .JobTitle;
+// variables are available as .Vars and through the var function. Array items
+// that render to blank strings are removed, so that list entries can be
+// conditional. Data without template actions is returned unchanged.
+func RenderApplicationData(appData ApplicationData, vars map[string]string) (ApplicationData, error) {
+	raw, err := json.Marshal(appData)
+	if err != nil {
+		return appData, fmt.Errorf("failed to encode application data: %w", err)
+	}
+	if !bytes.Contains(raw, []byte(templateMarker)) {
+		return appData, nil
+	}
+
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	renderer := &templateRenderer{data: TemplateData{
+		Name:         appData.Name,
+		Email:        appData.Email,
+		JobTitle:     appData.JobTitle,
+		FinalAttempt: appData.FinalAttempt != nil && *appData.FinalAttempt,
+		Vars:         vars,
+	}}
+
+	for _, field := range []struct {
+		path  string
+		value *string
+	}{
+		{"name", &appData.Name},
+		{"email", &appData.Email},
+		{"job_title", &appData.JobTitle},
+	} {
+		if *field.value, err = renderer.render(field.path, *field.value); err != nil {
+			return appData, err
+		}
+	}
+	renderer.data.Name, renderer.data.Email, renderer.data.JobTitle = appData.Name, appData.Email, appData.JobTitle
+
+	if appData.ExtraInformation != nil {
+		// Decode into generic values so that typed extra information is rendered too
+		extra, err := json.Marshal(appData.ExtraInformation)
+		if err != nil {
+			return appData, fmt.Errorf("failed to encode extra_information: %w", err)
+		}
+		var document interface{}
+		if err := json.Unmarshal(extra, &document); err != nil {
+			return appData, fmt.Errorf("failed to decode extra_information: %w", err)
+		}
+		if appData.ExtraInformation, err = renderer.renderValue("extra_information", document); err != nil {
+			return appData, err
+		}
+	}
+
+	attachments := make([]Attachment, len(appData.Attachments))
+	for i, attachment := range appData.Attachments {
+		path := fmt.Sprintf("attachments[%d]", i)
+		if attachment.Field, err = renderer.render(path+".field", attachment.Field); err != nil {
+			return appData, err
+		}
+		if attachment.Path, err = renderer.render(path+".path", attachment.Path); err != nil {
+			return appData, err
+		}
+		attachments[i] = attachment
+	}
+	if appData.Attachments != nil {
+		appData.Attachments = attachments
+	}
+
+	return appData, nil
+}
+
+// templateRenderer renders template strings against the same data
+type templateRenderer struct {
+	data TemplateData
+}
+
+// renderValue renders the string values below value, dropping blank array items
+func (r *templateRenderer) renderValue(path string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			rendered, err := r.renderValue(path+"."+key, v[key])
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+		return v, nil
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for i, child := range v {
+			rendered, err := r.renderValue(fmt.Sprintf("%s[%d]", path, i), child)
+			if err != nil {
+				return nil, err
+			}
+			if s, ok := child.(string); ok && strings.Contains(s, templateMarker) && strings.TrimSpace(rendered.(string)) == "" {
+				continue
+			}
+			items = append(items, rendered)
+		}
+		return items, nil
+	case string:
+		return r.render(path, v)
+	default:
+		return value, nil
+	}
+}
+
+// render executes s as a template named after its JSON path
+func (r *templateRenderer) render(path, s string) (string, error) {
+	if !strings.Contains(s, templateMarker) {
+		return s, nil
+	}
+
+	tmpl, err := template.New(path).Option("missingkey=error").Funcs(r.funcs()).Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, r.data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// funcs returns the functions available to templates
+func (r *templateRenderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		// var returns a variable, or the default when it is not set
+		"var": func(key string, defaults ...string) (string, error) {
+			if value, ok := r.data.Vars[key]; ok {
+				return value, nil
+			}
+			if len(defaults) > 0 {
+				return defaults[0], nil
+			}
+			return "", fmt.Errorf("variable %q is not set (use --var %s=value)", key, key)
+		},
+		"contains":  strings.Contains,
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+		"lower":     strings.ToLower,
+		"upper":     strings.ToUpper,
+	}
+}
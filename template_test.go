@@ -0,0 +1,162 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// templatedApplication emphasises different projects for backend and frontend roles
+func templatedApplication(jobTitle string) ApplicationData {
+	return ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: jobTitle,
+		ExtraInformation: map[string]interface{}{
+			"why_hire_me": `I want to work on {{if contains (lower .JobTitle) "backend"}}APIs{{else}}interfaces{{end}} at {{var "company" "MiTimes"}}.`,
+			"experience": map[string]interface{}{
+				"years_of_experience": 5.0,
+				"key_projects": []interface{}{
+					"Design system",
+					`{{if contains .JobTitle "Backend"}}Payments API{{end}}`,
+					`{{if contains .JobTitle "Frontend"}}Dashboard rewrite{{end}}`,
+				},
+			},
+		},
+	}
+}
+
+func TestRenderApplicationData(t *testing.T) {
+	tests := []struct {
+		jobTitle string
+		vars     map[string]string
+		whyHire  string
+		projects []interface{}
+	}{
+		{"Backend Engineer", nil, "I want to work on APIs at MiTimes.", []interface{}{"Design system", "Payments API"}},
+		{"Frontend Engineer", map[string]string{"company": "Acme"}, "I want to work on interfaces at Acme.", []interface{}{"Design system", "Dashboard rewrite"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.jobTitle, func(t *testing.T) {
+			rendered, err := RenderApplicationData(templatedApplication(tt.jobTitle), tt.vars)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			extra := rendered.ExtraInformation.(map[string]interface{})
+			if extra["why_hire_me"] != tt.whyHire {
+				t.Errorf("Expected %q, got %q", tt.whyHire, extra["why_hire_me"])
+			}
+			projects := extra["experience"].(map[string]interface{})["key_projects"]
+			if !reflect.DeepEqual(projects, tt.projects) {
+				t.Errorf("Expected projects %v, got %v", tt.projects, projects)
+			}
+		})
+	}
+}
+
+func TestRenderApplicationDataTopLevelFields(t *testing.T) {
+	appData := ApplicationData{
+		Name:             "John Doe",
+		Email:            "john@example.com",
+		JobTitle:         `{{var "role"}}`,
+		ExtraInformation: map[string]interface{}{"summary": "{{.Name}} applying for {{.JobTitle}}"},
+		Attachments:      []Attachment{{Field: "resume", Path: `resume-{{var "role" | lower}}.pdf`}},
+	}
+
+	rendered, err := RenderApplicationData(appData, map[string]string{"role": "SRE"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if rendered.JobTitle != "SRE" {
+		t.Errorf("Expected job title SRE, got %q", rendered.JobTitle)
+	}
+	if summary := rendered.ExtraInformation.(map[string]interface{})["summary"]; summary != "John Doe applying for SRE" {
+		t.Errorf("Expected the rendered job title in extra information, got %q", summary)
+	}
+	if rendered.Attachments[0].Path != "resume-sre.pdf" {
+		t.Errorf("Expected rendered attachment path, got %q", rendered.Attachments[0].Path)
+	}
+	if appData.JobTitle != `{{var "role"}}` {
+		t.Error("Expected the original data to be left unchanged")
+	}
+}
+
+func TestRenderApplicationDataErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"missing variable", `{{var "team"}}`, `variable "team" is not set`},
+		{"missing map key", `{{.Vars.team}}`, `extra_information.note`},
+		{"invalid template", `{{if}}`, "invalid template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appData := ApplicationData{ExtraInformation: map[string]interface{}{"note": tt.value}}
+			_, err := RenderApplicationData(appData, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRenderApplicationDataWithoutTemplates(t *testing.T) {
+	appData := createDefaultApplicationData("John Doe", "john@example.com", "Software Engineer", nil)
+	rendered, err := RenderApplicationData(appData, map[string]string{"unused": "value"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !reflect.DeepEqual(rendered, appData) {
+		t.Errorf("Expected data without templates to be unchanged, got %+v", rendered)
+	}
+}
+
+func TestParseTemplateVars(t *testing.T) {
+	vars, err := parseTemplateVars([]string{"team=platform", "note=a=b", "team=payments"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !reflect.DeepEqual(vars, map[string]string{"team": "payments", "note": "a=b"}) {
+		t.Errorf("Unexpected vars %v", vars)
+	}
+
+	for _, pair := range []string{"team", "=value"} {
+		if _, err := parseTemplateVars([]string{pair}); err == nil {
+			t.Errorf("Expected an error for %q", pair)
+		}
+	}
+}
+
+func TestTemplateVarsFromFlagsAndValidate(t *testing.T) {
+	dir := t.TempDir()
+	data := `{
+  "name": "John Doe",
+  "email": "{{var \"email\"}}",
+  "job_title": "Backend Engineer"
+}`
+	filename := filepath.Join(dir, "applicant.json")
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	configResult, err := LoadConfigFromArgs([]string{"--var", "email=john@example.com", "validate", filename})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if configResult.Vars["email"] != "john@example.com" {
+		t.Fatalf("Expected the --var value, got %v", configResult.Vars)
+	}
+
+	if results := ValidateFiles([]string{filename}, nil, DataOptions{Vars: configResult.Vars}); !results[0].Passed() {
+		t.Errorf("Expected the rendered data file to pass, got %v", results[0].Err)
+	}
+	if results := ValidateFiles([]string{filename}, nil, DataOptions{}); results[0].Passed() {
+		t.Error("Expected the data file to fail without the variable")
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CachedToken represents a token stored in the token cache
+type CachedToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TokenCache persists authorization tokens between runs, keyed by secret URL.
+// A nil *TokenCache is valid and behaves as a disabled cache.
+type TokenCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+// DefaultTokenCachePath returns the token cache file under the user cache directory
+func DefaultTokenCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "micv", "tokens.json"), nil
+}
+
+// NewTokenCache creates a token cache stored at path with the given TTL
+func NewTokenCache(path string, ttl time.Duration) *TokenCache {
+	return &TokenCache{
+		path: path,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// tokenCachePath returns the configured cache file or the default location
+func tokenCachePath(config *Config) (string, error) {
+	if config.TokenCacheFile != "" {
+		return config.TokenCacheFile, nil
+	}
+	return DefaultTokenCachePath()
+}
+
+// newTokenCacheFromConfig creates the token cache described by config, or nil when disabled
+func newTokenCacheFromConfig(config *Config) (*TokenCache, error) {
+	if config.TokenCacheTTL <= 0 {
+		return nil, nil
+	}
+
+	path, err := tokenCachePath(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTokenCache(path, time.Duration(config.TokenCacheTTL)*time.Second), nil
+}
+
+// Get returns a non-expired token for key
+func (c *TokenCache) Get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.load()
+	if err != nil {
+		return "", false
+	}
+
+	entry, ok := entries[key]
+	if !ok || !c.now().Before(entry.ExpiresAt) {
+		return "", false
+	}
+	return entry.Token, true
+}
+
+// Put stores token for key until the cache TTL elapses
+func (c *TokenCache) Put(key, token string) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	entries[key] = CachedToken{Token: token, ExpiresAt: c.now().Add(c.ttl)}
+	return c.save(entries)
+}
+
+// Invalidate removes the token stored for key
+func (c *TokenCache) Invalidate(key string) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+	delete(entries, key)
+	return c.save(entries)
+}
+
+// Clear removes the cache file
+func (c *TokenCache) Clear() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove token cache: %w", err)
+	}
+	return nil
+}
+
+// Entries returns all cached tokens, including expired ones
+func (c *TokenCache) Entries() (map[string]CachedToken, error) {
+	if c == nil {
+		return map[string]CachedToken{}, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.load()
+}
+
+// Path returns the cache file location
+func (c *TokenCache) Path() string {
+	if c == nil {
+		return ""
+	}
+	return c.path
+}
+
+// load reads the cache file, treating a missing file as empty
+func (c *TokenCache) load() (map[string]CachedToken, error) {
+	entries := make(map[string]CachedToken)
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode token cache: %w", err)
+	}
+	return entries, nil
+}
+
+// save writes the cache file with owner-only permissions
+func (c *TokenCache) save(entries map[string]CachedToken) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token cache: %w", err)
+	}
+
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return os.Chmod(c.path, 0600)
+}
+
+// maskToken hides all but the first few characters of a token
+func maskToken(token string) string {
+	const visible = 4
+	if len(token) <= visible {
+		return "****"
+	}
+	return token[:visible] + "****"
+}
+
+// runTokenCommand handles the `token show` and `token clear` commands
+func runTokenCommand(config *Config, action string) error {
+	// Inspecting or clearing the cache works even when caching is disabled
+	path, err := tokenCachePath(config)
+	if err != nil {
+		return err
+	}
+	cache := NewTokenCache(path, time.Duration(config.TokenCacheTTL)*time.Second)
+
+	switch action {
+	case "show":
+		entries, err := cache.Entries()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("📁 Token cache: %s\n", cache.Path())
+		if len(entries) == 0 {
+			fmt.Println("   (empty)")
+			return nil
+		}
+
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		now := time.Now()
+		for _, key := range keys {
+			entry := entries[key]
+			status := "valid"
+			if !now.Before(entry.ExpiresAt) {
+				status = "expired"
+			}
+			fmt.Printf("   %s\n", key)
+			fmt.Printf("      token: %s (%s, expires %s)\n", maskToken(entry.Token), status, entry.ExpiresAt.Format(time.RFC3339))
+		}
+		return nil
+	case "clear":
+		if err := cache.Clear(); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Token cache cleared: %s\n", cache.Path())
+		return nil
+	default:
+		return fmt.Errorf("unknown token command: %s (expected show or clear)", action)
+	}
+}
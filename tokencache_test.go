@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "micv", "tokens.json")
+	cache := NewTokenCache(path, time.Minute)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	if _, ok := cache.Get("https://example.com/secret"); ok {
+		t.Error("Expected cache miss on empty cache")
+	}
+
+	if err := cache.Put("https://example.com/secret", "token123"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected cache file to exist: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected cache file mode 0600, got %o", info.Mode().Perm())
+	}
+
+	token, ok := cache.Get("https://example.com/secret")
+	if !ok || token != "token123" {
+		t.Errorf("Expected cached token 'token123', got %q (hit=%v)", token, ok)
+	}
+
+	if _, ok := cache.Get("https://other.example.com/secret"); ok {
+		t.Error("Expected cache miss for a different secret URL")
+	}
+
+	// Expire the entry
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.Get("https://example.com/secret"); ok {
+		t.Error("Expected cache miss after TTL elapsed")
+	}
+
+	// Invalidate and clear
+	now = now.Add(-2 * time.Minute)
+	if err := cache.Invalidate("https://example.com/secret"); err != nil {
+		t.Fatalf("Invalidate failed: %v", err)
+	}
+	if _, ok := cache.Get("https://example.com/secret"); ok {
+		t.Error("Expected cache miss after invalidation")
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected cache file to be removed")
+	}
+}
+
+func TestNilTokenCache(t *testing.T) {
+	var cache *TokenCache
+
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Expected nil cache to miss")
+	}
+	if err := cache.Put("key", "token"); err != nil {
+		t.Errorf("Expected nil cache Put to be a no-op, got: %v", err)
+	}
+	if err := cache.Invalidate("key"); err != nil {
+		t.Errorf("Expected nil cache Invalidate to be a no-op, got: %v", err)
+	}
+}
+
+func TestApplicationServiceTokenCache(t *testing.T) {
+	tests := []struct {
+		name             string
+		cachedToken      string
+		expectedFetches  int
+		expectedSubmits  int
+		expectedAuthUsed []string
+	}{
+		{
+			name:             "valid cached token skips secret endpoint",
+			cachedToken:      "fresh-token",
+			expectedFetches:  0,
+			expectedSubmits:  1,
+			expectedAuthUsed: []string{"fresh-token"},
+		},
+		{
+			name:             "stale cached token is replaced after rejection",
+			cachedToken:      "stale-token",
+			expectedFetches:  1,
+			expectedSubmits:  2,
+			expectedAuthUsed: []string{"stale-token", "fresh-token"},
+		},
+		{
+			name:             "empty cache fetches and stores token",
+			cachedToken:      "",
+			expectedFetches:  1,
+			expectedSubmits:  1,
+			expectedAuthUsed: []string{"fresh-token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := NewMockDependencies()
+			deps.tokenCache = NewTokenCache(filepath.Join(t.TempDir(), "tokens.json"), time.Hour)
+			if tt.cachedToken != "" {
+				deps.tokenCache.Put(deps.config.SecretURL, tt.cachedToken)
+			}
+
+			fetches := 0
+			var authUsed []string
+			deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+				fetches++
+				return createResponse(200, `{"result":"fresh-token"}`), nil
+			}
+			deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+				authUsed = append(authUsed, req.Header.Get("Authorization"))
+				if req.Header.Get("Authorization") != "fresh-token" {
+					return createResponse(401, `{"error":"unauthorized"}`), nil
+				}
+				return createResponse(200, `{"status":"success"}`), nil
+			}
+
+			err := NewApplicationService(deps).SubmitApplication(context.Background(), ApplicationData{
+				Name:     "John Doe",
+				Email:    "john@example.com",
+				JobTitle: "Software Engineer",
+			})
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			if fetches != tt.expectedFetches {
+				t.Errorf("Expected %d secret fetches, got %d", tt.expectedFetches, fetches)
+			}
+			if len(authUsed) != tt.expectedSubmits {
+				t.Fatalf("Expected %d submissions, got %d", tt.expectedSubmits, len(authUsed))
+			}
+			for i, auth := range tt.expectedAuthUsed {
+				if authUsed[i] != auth {
+					t.Errorf("Submission %d: expected Authorization %q, got %q", i, auth, authUsed[i])
+				}
+			}
+
+			if token, ok := deps.tokenCache.Get(deps.config.SecretURL); !ok || token != "fresh-token" {
+				t.Errorf("Expected fresh token to be cached, got %q (hit=%v)", token, ok)
+			}
+		})
+	}
+}
+
+func TestApplicationServiceRejectedTokenIsNotRetried(t *testing.T) {
+	deps := NewMockDependencies()
+	submits := 0
+	deps.httpClient.GetFunc = func(url string) (*http.Response, error) {
+		return createResponse(200, `{"result":"token123"}`), nil
+	}
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		submits++
+		return createResponse(403, `{"error":"forbidden"}`), nil
+	}
+
+	appData := ApplicationData{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		JobTitle: "Software Engineer",
+	}
+
+	// Without a cached token the rejection is a non-success status, as before token caching
+	if err := NewApplicationService(deps).SubmitApplication(context.Background(), appData); err != nil {
+		t.Errorf("Expected a rejected fresh token to be reported as a non-success status, got: %v", err)
+	}
+	if submits != 1 {
+		t.Errorf("Expected a single submission attempt, got %d", submits)
+	}
+
+	// A cached token that is still rejected after a refresh is an error
+	submits = 0
+	deps.tokenCache = NewTokenCache(filepath.Join(t.TempDir(), "tokens.json"), time.Hour)
+	deps.tokenCache.Put(deps.config.SecretURL, "stale-token")
+	err := NewApplicationService(deps).SubmitApplication(context.Background(), appData)
+	appErr, ok := err.(*AppError)
+	if !ok || appErr.Code != ErrCodeAuth {
+		t.Fatalf("Expected %s AppError, got: %v", ErrCodeAuth, err)
+	}
+	if submits != 2 {
+		t.Errorf("Expected the cached and the fresh token to be sent once each, got %d", submits)
+	}
+	if _, ok := deps.tokenCache.Get(deps.config.SecretURL); ok {
+		t.Error("Expected the rejected token to be removed from the cache")
+	}
+}
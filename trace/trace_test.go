@@ -0,0 +1,196 @@
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// memoryExporter keeps exported spans in memory
+type memoryExporter struct {
+	spans []SpanData
+}
+
+func (e *memoryExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func TestTracerParentChild(t *testing.T) {
+	exporter := &memoryExporter{}
+	tracer := NewTracer(exporter)
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	root.SetAttribute("run_id", "abc")
+	childCtx, child := Start(ctx, "child")
+	child.RecordError(errors.New("failed"))
+
+	header := make(http.Header)
+	Inject(childCtx, header)
+	traceparent := header.Get(TraceparentHeader)
+	if !regexp.MustCompile(`^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`).MatchString(traceparent) {
+		t.Errorf("Invalid traceparent %q", traceparent)
+	}
+	if traceparent != child.Context().Traceparent() {
+		t.Errorf("Expected the traceparent of the child span, got %q", traceparent)
+	}
+
+	child.End()
+	child.End()
+	root.End()
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(exporter.spans))
+	}
+	childData, rootData := exporter.spans[0], exporter.spans[1]
+	if childData.TraceID != rootData.TraceID || childData.ParentSpanID != rootData.SpanID || rootData.ParentSpanID != "" {
+		t.Errorf("Expected child of root in the same trace, got %+v and %+v", childData, rootData)
+	}
+	if childData.Error != "failed" || rootData.Attributes["run_id"] != "abc" {
+		t.Errorf("Unexpected span data %+v and %+v", childData, rootData)
+	}
+	traceID := root.Context().TraceID
+	if rootData.TraceID != hex.EncodeToString(traceID[:]) {
+		t.Errorf("Expected trace ID %x, got %s", traceID, rootData.TraceID)
+	}
+
+	// Flushed spans are not exported again
+	tracer.Flush(context.Background())
+	if len(exporter.spans) != 2 {
+		t.Errorf("Expected no new spans, got %d", len(exporter.spans))
+	}
+}
+
+func TestTracingDisabled(t *testing.T) {
+	var tracer *Tracer
+	ctx, span := tracer.Start(context.Background(), "root")
+	if span != nil || SpanFromContext(ctx) != nil {
+		t.Fatal("Expected no span without a tracer")
+	}
+	span.SetAttribute("key", "value")
+	span.RecordError(errors.New("failed"))
+	span.End()
+
+	header := make(http.Header)
+	Inject(ctx, header)
+	if header.Get(TraceparentHeader) != "" {
+		t.Error("Expected no traceparent without a span")
+	}
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+}
+
+func TestJSONFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	exporter := JSONFileExporter{Path: path}
+	for _, name := range []string{"first", "second"} {
+		if err := exporter.Export(context.Background(), []SpanData{{Name: name}}); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected trace file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected spans to be appended as 2 lines, got %q", data)
+	}
+	var span SpanData
+	if err := json.Unmarshal([]byte(lines[1]), &span); err != nil || span.Name != "second" {
+		t.Errorf("Unexpected span %+v: %v", span, err)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var request struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string
+					Value map[string]interface{}
+				}
+			}
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID           string `json:"traceId"`
+					ParentSpanID      string `json:"parentSpanId"`
+					Name              string
+					StartTimeUnixNano string
+					Attributes        []struct {
+						Key   string
+						Value map[string]interface{}
+					}
+					Status struct {
+						Code    int
+						Message string
+					}
+				}
+			}
+		}
+	}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Invalid OTLP payload: %v", err)
+		}
+	}))
+	defer collector.Close()
+
+	exporter := &memoryExporter{}
+	tracer := NewTracer(exporter, OTLPExporter{Endpoint: collector.URL, ServiceName: "micv"})
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := Start(ctx, "child")
+	child.SetAttribute("attempt", 2)
+	child.RecordError(errors.New("failed"))
+	child.End()
+	root.End()
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(request.ResourceSpans) != 1 || len(request.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Unexpected OTLP payload %+v", request)
+	}
+	resource := request.ResourceSpans[0].Resource.Attributes
+	if len(resource) != 1 || resource[0].Key != "service.name" || resource[0].Value["stringValue"] != "micv" {
+		t.Errorf("Unexpected resource attributes %+v", resource)
+	}
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 || spans[0].Name != "child" || spans[0].ParentSpanID != exporter.spans[1].SpanID {
+		t.Fatalf("Unexpected spans %+v", spans)
+	}
+	if spans[0].Status.Code != 2 || spans[0].Status.Message != "failed" || spans[0].StartTimeUnixNano == "" {
+		t.Errorf("Unexpected child span %+v", spans[0])
+	}
+	if len(spans[0].Attributes) != 1 || spans[0].Attributes[0].Value["intValue"] != "2" {
+		t.Errorf("Unexpected attributes %+v", spans[0].Attributes)
+	}
+}
+
+func TestOTLPExporterURL(t *testing.T) {
+	for endpoint, expected := range map[string]string{
+		"http://localhost:4318":           "http://localhost:4318/v1/traces",
+		"http://localhost:4318/":          "http://localhost:4318/v1/traces",
+		"http://collector/otlp/v1/traces": "http://collector/otlp/v1/traces",
+	} {
+		if got := (OTLPExporter{Endpoint: endpoint}).URL(); got != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, endpoint, got)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"micv/trace"
+)
+
+// tracingExportTimeout bounds the export of spans at the end of a run
+const tracingExportTimeout = 10 * time.Second
+
+// TracingConfig selects where trace spans are exported
+type TracingConfig struct {
+	File         string `json:"file,omitempty"`
+	OTLPEndpoint string `json:"otlp_endpoint,omitempty"`
+}
+
+// enabled reports whether spans are exported anywhere
+func (t TracingConfig) enabled() bool {
+	return t.File != "" || t.OTLPEndpoint != ""
+}
+
+// newTracerFromConfig creates a tracer for the configured exporters, or returns
+// nil when tracing is disabled. Like webhooks, the collector is reached with a
+// plain HTTP client rather than the settings of the application endpoints.
+func newTracerFromConfig(config TracingConfig) *trace.Tracer {
+	if !config.enabled() {
+		return nil
+	}
+
+	var exporters []trace.Exporter
+	if config.File != "" {
+		exporters = append(exporters, trace.JSONFileExporter{Path: config.File})
+	}
+	if config.OTLPEndpoint != "" {
+		exporters = append(exporters, trace.OTLPExporter{
+			Endpoint:    config.OTLPEndpoint,
+			ServiceName: "micv",
+			Client:      NewHTTPClientWithTimeout(tracingExportTimeout),
+		})
+	}
+	return trace.NewTracer(exporters...)
+}
+
+// flushTracer exports the spans of the run; failures are reported but do not fail the run
+func flushTracer(tracer *trace.Tracer, logger *Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracingExportTimeout)
+	defer cancel()
+
+	if err := tracer.Flush(ctx); err != nil {
+		logger.Warn("Failed to export trace spans", "error", err)
+		fmt.Printf("⚠️  Failed to export trace spans: %v\n", err)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"micv/trace"
+)
+
+// readSpans decodes the spans written by a trace.JSONFileExporter
+func readSpans(t *testing.T, path string) []trace.SpanData {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected trace file: %v", err)
+	}
+
+	var spans []trace.SpanData
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var span trace.SpanData
+		if err := json.Unmarshal([]byte(line), &span); err != nil {
+			t.Fatalf("Invalid span %q: %v", line, err)
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+func TestApplicationRunTracesSubmission(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+
+	deps := NewMockDependencies()
+	deps.config.Retry = RetrySettings{MaxAttempts: 3, InitialDelayMS: 1, MaxDelayMS: 1}
+	deps.tracer = newTracerFromConfig(TracingConfig{File: path})
+
+	// Traced token requests are sent with Do so that they carry a traceparent
+	var traceparents []string
+	tokenCalls := 0
+	deps.httpClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		traceparents = append(traceparents, req.Header.Get("traceparent"))
+		if req.Method == http.MethodGet {
+			tokenCalls++
+			if tokenCalls == 1 {
+				return nil, errors.New("connection reset")
+			}
+			return createResponse(200, `{"result":"token123"}`), nil
+		}
+		return createResponse(201, `{"status":"success"}`), nil
+	}
+
+	appData := ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Software Engineer"}
+	if err := NewApplication(deps).Run(context.Background(), appData); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	flushTracer(deps.tracer, deps.logger)
+
+	spans := readSpans(t, path)
+	byName := make(map[string][]trace.SpanData)
+	for _, span := range spans {
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+
+	root := byName["Application.Run"]
+	if len(root) != 1 || root[0].ParentSpanID != "" || root[0].Attributes["run_id"] == "" {
+		t.Fatalf("Expected one root Application.Run span, got %+v", spans)
+	}
+	parents := map[string]string{
+		"ConfigService.ValidateConfig":                "Application.Run",
+		"ApplicationService.SubmitApplication":        "Application.Run",
+		"ApplicationService.validateApplication":      "ApplicationService.SubmitApplication",
+		"ApplicationService.fetchTokenWithResilience": "ApplicationService.SubmitApplication",
+		"ApplicationService.submitWithResilience":     "ApplicationService.SubmitApplication",
+		"fetch_token.attempt":                         "ApplicationService.fetchTokenWithResilience",
+		"submit.attempt":                              "ApplicationService.submitWithResilience",
+	}
+	for name, parent := range parents {
+		if len(byName[name]) == 0 {
+			t.Errorf("Expected a %s span", name)
+			continue
+		}
+		for _, span := range byName[name] {
+			if span.TraceID != root[0].TraceID || span.ParentSpanID != byName[parent][0].SpanID {
+				t.Errorf("Expected %s to be a child of %s, got %+v", name, parent, span)
+			}
+		}
+	}
+
+	attempts := byName["fetch_token.attempt"]
+	if len(attempts) != 2 || attempts[0].Error == "" || attempts[1].Error != "" {
+		t.Errorf("Expected a failed and a successful token attempt, got %+v", attempts)
+	}
+
+	// Each request carries the traceparent of its attempt span
+	if len(traceparents) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(traceparents))
+	}
+	submitAttempt := byName["submit.attempt"][0]
+	expected := "00-" + submitAttempt.TraceID + "-" + submitAttempt.SpanID + "-01"
+	if traceparents[2] != expected {
+		t.Errorf("Expected traceparent %s, got %s", expected, traceparents[2])
+	}
+	if submitAttempt.Attributes["http.status_code"] != float64(201) {
+		t.Errorf("Expected status code attribute, got %+v", submitAttempt.Attributes)
+	}
+}
+
+func TestTracingConfigFromFlagsAndEnvironment(t *testing.T) {
+	t.Setenv("MICV_OTLP_ENDPOINT", "http://localhost:4318")
+
+	configResult, err := LoadConfigFromArgs([]string{"--trace-file", "spans.jsonl", "submit", "John Doe", "john@example.com", "Engineer"})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if configResult.Config.Tracing != (TracingConfig{File: "spans.jsonl", OTLPEndpoint: "http://localhost:4318"}) {
+		t.Errorf("Unexpected tracing config %+v", configResult.Config.Tracing)
+	}
+	if source := configResult.Sources.Source("tracing.otlp_endpoint"); source != "env MICV_OTLP_ENDPOINT" {
+		t.Errorf("Expected tracing.otlp_endpoint from the environment, got %q", source)
+	}
+
+	config := DefaultConfig()
+	// Spans never carry the token, so plain HTTP needs no --allow-insecure
+	config.Tracing.OTLPEndpoint = "http://collector.example.com:4318"
+	if err := ValidateConfig(config); err != nil {
+		t.Errorf("Expected plain HTTP collector on a remote host to be valid, got %v", err)
+	}
+
+	config.Tracing.OTLPEndpoint = "grpc://collector.example.com:4317"
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected an error for a collector URL with an unsupported scheme")
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"micv/client"
+)
+
+// Kinds of files checked by the validate command
+const (
+	FileKindData   = "data"
+	FileKindConfig = "config"
+)
+
+// FileValidation is the outcome of validating one file
+type FileValidation struct {
+	File string
+	Kind string
+	Err  error
+}
+
+// Passed reports whether the file is valid
+func (v FileValidation) Passed() bool {
+	return v.Err == nil
+}
+
+// stringListFlag collects the values of a repeatable flag
+type stringListFlag []string
+
+// String implements flag.Value
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set implements flag.Value
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// DataOptions controls how the validate command renders and checks data files
+type DataOptions struct {
+	// Vars are the template variables given with --var
+	Vars map[string]string
+	// Attachments holds the limits that referenced attachments are checked against
+	Attachments AttachmentConfig
+}
+
+// ValidateFiles validates every data and config file matched by the given
+// paths or glob patterns, without any network access
+func ValidateFiles(dataPatterns, configPatterns []string, options DataOptions) []FileValidation {
+	validateData := func(filename string) error {
+		return validateDataFile(filename, options)
+	}
+
+	var results []FileValidation
+	results = append(results, validateMatchingFiles(configPatterns, FileKindConfig, validateConfigFile)...)
+	results = append(results, validateMatchingFiles(dataPatterns, FileKindData, validateData)...)
+	return results
+}
+
+// validateMatchingFiles expands each pattern and validates the matched files;
+// a pattern that matches nothing is reported as a failure
+func validateMatchingFiles(patterns []string, kind string, validate func(string) error) []FileValidation {
+	var results []FileValidation
+	for _, pattern := range patterns {
+		files, err := expandFilePattern(pattern)
+		if err != nil {
+			results = append(results, FileValidation{File: pattern, Kind: kind, Err: err})
+			continue
+		}
+		for _, file := range files {
+			results = append(results, FileValidation{File: file, Kind: kind, Err: validate(file)})
+		}
+	}
+	return results
+}
+
+// expandFilePattern returns the files matching a path or glob pattern
+func expandFilePattern(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	if len(matches) == 0 {
+		if _, statErr := os.Stat(pattern); statErr != nil {
+			return nil, fmt.Errorf("no files match")
+		}
+		matches = []string{pattern}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// validateDataFile checks that a data file loads and renders, passes all field
+// rules and references attachments that can be sent
+func validateDataFile(filename string, options DataOptions) error {
+	appData, err := LoadApplicationData(filename)
+	if err != nil {
+		return err
+	}
+	rendered, err := RenderApplicationData(*appData, options.Vars)
+	if err != nil {
+		return err
+	}
+	if result := validateApplicationDataFunctional(rendered); result.IsError() {
+		return result.Error
+	}
+	return client.CheckAttachments(rendered.Attachments, options.Attachments.options())
+}
+
+// validateConfigFile checks the base configuration of a file and every profile in it
+func validateConfigFile(filename string) error {
+	config := DefaultConfig()
+	if err := loadConfigFromFile(filename, config); err != nil {
+		return err
+	}
+	if err := ValidateConfig(config); err != nil {
+		return err
+	}
+
+	data, err := readConfigDocument(filename)
+	if err != nil {
+		return err
+	}
+	var file configProfiles
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to decode config profiles: %w", err)
+	}
+
+	for _, name := range sortedKeys(file.Profiles) {
+		config := DefaultConfig()
+		if err := loadConfigProfileFromFile(filename, name, config); err != nil {
+			return err
+		}
+		if err := ValidateConfig(config); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of profiles in sorted order
+func sortedKeys(profiles map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(profiles))
+	for key := range profiles {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printFileValidations writes a pass/fail line per file and a summary
+func printFileValidations(w io.Writer, results []FileValidation) int {
+	failed := 0
+	for _, result := range results {
+		if result.Passed() {
+			fmt.Fprintf(w, "✅ %s (%s)\n", result.File, result.Kind)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "❌ %s (%s): %v\n", result.File, result.Kind, result.Err)
+	}
+	fmt.Fprintf(w, "\n%d of %d files passed\n", len(results)-failed, len(results))
+	return failed
+}
+
+// defineValidateFlags defines the options of the validate command on fs
+func defineValidateFlags(fs *flag.FlagSet) (configPatterns *stringListFlag, dataFile *string) {
+	configPatterns = &stringListFlag{}
+	fs.Var(configPatterns, "config-file", "Config file or glob to validate (repeatable)")
+	dataFile = fs.String("data", "", "Data file to validate")
+	return configPatterns, dataFile
+}
+
+// runValidateCommand validates the given data and config files, or the
+// effective configuration and application data when no files are given
+func runValidateCommand(configResult *ConfigResult) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	configPatterns, dataFile := defineValidateFlags(fs)
+	fs.Usage = func() {
+		printCommandHelp(fs.Output(), "validate")
+	}
+	if err := fs.Parse(configResult.Args); err != nil {
+		return err
+	}
+
+	dataPatterns := fs.Args()
+	if *dataFile != "" {
+		dataPatterns = append([]string{*dataFile}, dataPatterns...)
+	}
+
+	if len(dataPatterns) == 0 && len(*configPatterns) == 0 {
+		return validateEffectiveConfiguration(configResult)
+	}
+
+	results := ValidateFiles(dataPatterns, *configPatterns, DataOptions{
+		Vars:        configResult.Vars,
+		Attachments: configResult.Config.Attachments,
+	})
+	if failed := printFileValidations(os.Stdout, results); failed > 0 {
+		return NewAppError(ErrCodeValidation, fmt.Sprintf("%d of %d files failed validation", failed, len(results)), nil)
+	}
+	return nil
+}
+
+// validateEffectiveConfiguration validates the loaded configuration and the
+// application data that submit would send
+func validateEffectiveConfiguration(configResult *ConfigResult) error {
+	if err := ValidateConfig(configResult.Config); err != nil {
+		appErr := NewAppError(ErrCodeConfig, "Invalid configuration", err)
+		if configResult.ConfigFile != "" {
+			appErr.WithContext("config_path", configResult.ConfigFile)
+		}
+		return appErr
+	}
+	fmt.Println("✅ Configuration is valid")
+
+	if configResult.DataDiscovered {
+		fmt.Printf("📄 Using data file: %s\n", configResult.DataFile)
+	}
+	appData, err := loadApplicationData(configResult)
+	if err != nil {
+		return err
+	}
+	if appData, err = RenderApplicationData(appData, configResult.Vars); err != nil {
+		return WrapValidationError(err, "application_data")
+	}
+	if result := validateApplicationDataFunctional(appData); result.IsError() {
+		return WrapValidationError(result.Error, "application_data")
+	}
+	if err := client.CheckAttachments(appData.Attachments, configResult.Config.Attachments.options()); err != nil {
+		return WrapValidationError(err, "attachments")
+	}
+	fmt.Println("✅ Application data is valid")
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package validate provides composable validation rules and the checks
+// applied to application data before it is submitted.
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"micv/model"
+)
+
+// ValidationRule represents a validation function
+type ValidationRule[T any] func(T) error
+
+// Validator provides functional validation capabilities
+type Validator[T any] struct {
+	rules []ValidationRule[T]
+}
+
+// NewValidator creates a new validator
+func NewValidator[T any]() *Validator[T] {
+	return &Validator[T]{rules: make([]ValidationRule[T], 0)}
+}
+
+// AddRule adds a validation rule
+func (v *Validator[T]) AddRule(rule ValidationRule[T]) *Validator[T] {
+	v.rules = append(v.rules, rule)
+	return v
+}
+
+// Validate runs all validation rules
+func (v *Validator[T]) Validate(value T) model.Result[T] {
+	for _, rule := range v.rules {
+		if err := rule(value); err != nil {
+			return model.NewError[T](err)
+		}
+	}
+	return model.NewResult(value)
+}
+
+// Common validation rules for application data
+func RequiredField(fieldName string) ValidationRule[string] {
+	return func(value string) error {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%s is required", fieldName)
+		}
+		return nil
+	}
+}
+
+func EmailFormat() ValidationRule[string] {
+	return func(value string) error {
+		if !strings.Contains(value, "@") || !strings.Contains(value, ".") {
+			return fmt.Errorf("invalid email format")
+		}
+		return nil
+	}
+}
+
+func MinLength(min int) ValidationRule[string] {
+	return func(value string) error {
+		if len(strings.TrimSpace(value)) < min {
+			return fmt.Errorf("must be at least %d characters", min)
+		}
+		return nil
+	}
+}
+
+// RequiredFields checks that name, email and job_title are present and not empty
+func RequiredFields(appData *model.ApplicationData) error {
+	var missingFields []string
+
+	if strings.TrimSpace(appData.Name) == "" {
+		missingFields = append(missingFields, "name")
+	}
+	if strings.TrimSpace(appData.Email) == "" {
+		missingFields = append(missingFields, "email")
+	}
+	if strings.TrimSpace(appData.JobTitle) == "" {
+		missingFields = append(missingFields, "job_title")
+	}
+
+	if len(missingFields) > 0 {
+		return fmt.Errorf("missing required fields: %s", strings.Join(missingFields, ", "))
+	}
+
+	return nil
+}
+
+// ApplicationData checks the required fields and the format of the applicant details
+func ApplicationData(data model.ApplicationData) model.Result[model.ApplicationData] {
+	nameValidator := NewValidator[string]().
+		AddRule(RequiredField("name")).
+		AddRule(MinLength(2))
+
+	emailValidator := NewValidator[string]().
+		AddRule(RequiredField("email")).
+		AddRule(EmailFormat())
+
+	jobTitleValidator := NewValidator[string]().
+		AddRule(RequiredField("job_title")).
+		AddRule(MinLength(3))
+
+	// Validate all fields
+	if result := nameValidator.Validate(data.Name); result.IsError() {
+		return model.NewError[model.ApplicationData](result.Error)
+	}
+
+	if result := emailValidator.Validate(data.Email); result.IsError() {
+		return model.NewError[model.ApplicationData](result.Error)
+	}
+
+	if result := jobTitleValidator.Validate(data.JobTitle); result.IsError() {
+		return model.NewError[model.ApplicationData](result.Error)
+	}
+
+	return model.NewResult(data)
+}
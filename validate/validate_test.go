@@ -0,0 +1,110 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"micv/model"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        ValidationRule[string]
+		value       string
+		expectError string
+	}{
+		{"required present", RequiredField("name"), "John", ""},
+		{"required blank", RequiredField("name"), "   ", "name is required"},
+		{"email valid", EmailFormat(), "john@example.com", ""},
+		{"email without at", EmailFormat(), "john.example.com", "invalid email format"},
+		{"email without dot", EmailFormat(), "john@example", "invalid email format"},
+		{"min length met", MinLength(3), " SRE ", ""},
+		{"min length trimmed", MinLength(3), " SE ", "at least 3 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule(tt.value)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestValidatorStopsAtFirstFailure(t *testing.T) {
+	var checked []string
+	rule := func(name string, fail bool) ValidationRule[string] {
+		return func(string) error {
+			checked = append(checked, name)
+			if fail {
+				return RequiredField(name)("")
+			}
+			return nil
+		}
+	}
+
+	result := NewValidator[string]().
+		AddRule(rule("first", false)).
+		AddRule(rule("second", true)).
+		AddRule(rule("third", true)).
+		Validate("value")
+
+	if result.IsSuccess() || result.Error.Error() != "second is required" {
+		t.Errorf("Expected the second rule's error, got: %v", result.Error)
+	}
+	if strings.Join(checked, ",") != "first,second" {
+		t.Errorf("Expected rules to stop at the first failure, checked %v", checked)
+	}
+
+	if result := NewValidator[string]().Validate("value"); result.IsError() || result.Value != "value" {
+		t.Errorf("Expected a validator without rules to pass, got %+v", result)
+	}
+}
+
+func TestRequiredFields(t *testing.T) {
+	if err := RequiredFields(&model.ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Engineer"}); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	err := RequiredFields(&model.ApplicationData{Name: "John Doe", JobTitle: " "})
+	if err == nil || err.Error() != "missing required fields: email, job_title" {
+		t.Errorf("Expected every missing field to be listed, got: %v", err)
+	}
+}
+
+func TestApplicationData(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        model.ApplicationData
+		expectError string
+	}{
+		{"valid", model.ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "Software Engineer"}, ""},
+		{"missing name", model.ApplicationData{Email: "john@example.com", JobTitle: "Software Engineer"}, "name is required"},
+		{"short name", model.ApplicationData{Name: "J", Email: "john@example.com", JobTitle: "Software Engineer"}, "at least 2 characters"},
+		{"invalid email", model.ApplicationData{Name: "John Doe", Email: "john", JobTitle: "Software Engineer"}, "invalid email format"},
+		{"short job title", model.ApplicationData{Name: "John Doe", Email: "john@example.com", JobTitle: "SE"}, "at least 3 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ApplicationData(tt.data)
+			if tt.expectError == "" {
+				if result.IsError() || result.Value.Name != tt.data.Name {
+					t.Errorf("Expected the data back, got %+v", result)
+				}
+				return
+			}
+			if result.IsSuccess() || !strings.Contains(result.Error.Error(), tt.expectError) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectError, result.Error)
+			}
+		})
+	}
+}
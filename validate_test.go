@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	validData := createDefaultApplicationData("John Doe", "john@example.com", "Software Engineer", nil)
+	if err := SaveApplicationData(validData, filepath.Join(dir, "valid.json")); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	invalidData := createDefaultApplicationData("John Doe", "not-an-email", "Software Engineer", nil)
+	if err := SaveApplicationData(invalidData, filepath.Join(dir, "invalid.json")); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	configDir := t.TempDir()
+	if err := SaveConfig(DefaultConfig(), filepath.Join(configDir, "config.json")); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	brokenProfile := `{"profiles": {"broken": {"application_url": "ftp://example.com/apply"}}}`
+	if err := os.WriteFile(filepath.Join(configDir, "profiles.json"), []byte(brokenProfile), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	results := ValidateFiles(
+		[]string{filepath.Join(dir, "*.json"), filepath.Join(dir, "missing-*.json")},
+		[]string{filepath.Join(configDir, "*.json")},
+		DataOptions{},
+	)
+
+	expected := map[string]bool{
+		filepath.Join(configDir, "config.json"):   true,
+		filepath.Join(configDir, "profiles.json"): false,
+		filepath.Join(dir, "invalid.json"):        false,
+		filepath.Join(dir, "valid.json"):          true,
+		filepath.Join(dir, "missing-*.json"):      false,
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d: %+v", len(expected), len(results), results)
+	}
+	for _, result := range results {
+		passed, ok := expected[result.File]
+		if !ok {
+			t.Errorf("Unexpected result for %s", result.File)
+			continue
+		}
+		if result.Passed() != passed {
+			t.Errorf("Expected %s passed=%v, got error %v", result.File, passed, result.Err)
+		}
+	}
+
+	var out bytes.Buffer
+	if failed := printFileValidations(&out, results); failed != 3 {
+		t.Errorf("Expected 3 failures, got %d", failed)
+	}
+	if !strings.Contains(out.String(), "2 of 5 files passed") {
+		t.Errorf("Expected summary line, got %q", out.String())
+	}
+}
+
+func TestValidateCommandFiles(t *testing.T) {
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "data.json")
+	appData := createDefaultApplicationData("John Doe", "john@example.com", "Software Engineer", nil)
+	if err := SaveApplicationData(appData, dataFile); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	if err := runValidateCommand(&ConfigResult{Config: DefaultConfig(), Args: []string{dataFile}}); err != nil {
+		t.Errorf("Expected valid data file, got %v", err)
+	}
+
+	err := runValidateCommand(&ConfigResult{Config: DefaultConfig(), Args: []string{filepath.Join(dir, "nothing-*.json")}})
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeValidation {
+		t.Errorf("Expected %s error, got %v", ErrCodeValidation, err)
+	}
+}
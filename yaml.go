@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// isYAMLFile reports whether filename has a YAML extension
+func isYAMLFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// yamlLine is a significant line of a YAML document
+type yamlLine struct {
+	number int
+	indent int
+	key    string
+	value  string
+}
+
+// yamlToJSON converts the YAML subset used by configuration files to JSON.
+// Only nested block mappings with scalar values are supported; sequences,
+// flow collections, anchors and multi-line strings are rejected.
+func yamlToJSON(data []byte) ([]byte, error) {
+	lines, err := scanYAMLLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	value, next, err := parseYAMLMapping(lines, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, fmt.Errorf("yaml line %d: unexpected indentation", lines[next].number)
+	}
+
+	return json.Marshal(value)
+}
+
+// scanYAMLLines splits a document into key/value lines, skipping blanks and comments
+func scanYAMLLines(doc string) ([]yamlLine, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(doc, "\n") {
+		number := i + 1
+		raw = strings.TrimRight(raw, " \r")
+
+		trimmed := strings.TrimLeft(raw, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("yaml line %d: tabs are not allowed for indentation", number)
+		}
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			return nil, fmt.Errorf("yaml line %d: sequences are not supported", number)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || (value != "" && !strings.HasPrefix(value, " ")) {
+			return nil, fmt.Errorf("yaml line %d: expected \"key: value\"", number)
+		}
+
+		lines = append(lines, yamlLine{
+			number: number,
+			indent: len(raw) - len(trimmed),
+			key:    unquoteYAMLKey(strings.TrimSpace(key)),
+			value:  stripYAMLComment(strings.TrimSpace(value)),
+		})
+	}
+	return lines, nil
+}
+
+// parseYAMLMapping parses the mapping whose keys start at indent
+func parseYAMLMapping(lines []yamlLine, start, indent int) (map[string]interface{}, int, error) {
+	mapping := make(map[string]interface{})
+
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, i, fmt.Errorf("yaml line %d: unexpected indentation", line.number)
+		}
+		if _, exists := mapping[line.key]; exists {
+			return nil, i, fmt.Errorf("yaml line %d: duplicate key %q", line.number, line.key)
+		}
+
+		if line.value != "" {
+			value, err := parseYAMLScalar(line.value)
+			if err != nil {
+				return nil, i, fmt.Errorf("yaml line %d: %w", line.number, err)
+			}
+			mapping[line.key] = value
+			i++
+			continue
+		}
+
+		// A key without a value opens a nested mapping, or is null if nothing is nested
+		if i+1 < len(lines) && lines[i+1].indent > indent {
+			nested, next, err := parseYAMLMapping(lines, i+1, lines[i+1].indent)
+			if err != nil {
+				return nil, next, err
+			}
+			mapping[line.key] = nested
+			i = next
+			continue
+		}
+		mapping[line.key] = nil
+		i++
+	}
+
+	return mapping, i, nil
+}
+
+// parseYAMLScalar converts a plain or quoted scalar to its JSON value
+func parseYAMLScalar(value string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double-quoted string %s", value)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return nil, fmt.Errorf("invalid single-quoted string %s", value)
+		}
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	case strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{"):
+		return nil, fmt.Errorf("flow collections are not supported")
+	case strings.HasPrefix(value, "&") || strings.HasPrefix(value, "*") || strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+		return nil, fmt.Errorf("anchors, aliases and block scalars are not supported")
+	}
+
+	switch value {
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, nil
+	}
+	return value, nil
+}
+
+// unquoteYAMLKey removes surrounding quotes from a mapping key
+func unquoteYAMLKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// stripYAMLComment removes a trailing " #" comment outside of quotes
+func stripYAMLComment(value string) string {
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || value[i-1] == ' '):
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
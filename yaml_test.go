@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	doc := `# micv configuration
+secret_url: https://au.mitimes.com/careers/apply/secret
+application_url: "https://au.mitimes.com/careers/apply"  # quoted
+timeout_seconds: 30
+tls:
+  min_version: '1.3'
+  insecure_skip_verify: false
+rate_limits:
+  "*":
+    requests_per_second: 0.5
+    burst: 2
+`
+
+	data, err := yamlToJSON([]byte(doc))
+	if err != nil {
+		t.Fatalf("yamlToJSON failed: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"secret_url":      "https://au.mitimes.com/careers/apply/secret",
+		"application_url": "https://au.mitimes.com/careers/apply",
+		"timeout_seconds": float64(30),
+		"tls": map[string]interface{}{
+			"min_version":          "1.3",
+			"insecure_skip_verify": false,
+		},
+		"rate_limits": map[string]interface{}{
+			"*": map[string]interface{}{
+				"requests_per_second": 0.5,
+				"burst":               float64(2),
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected conversion:\n got: %v\nwant: %v", got, expected)
+	}
+}
+
+func TestYAMLToJSONUnsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		contains string
+	}{
+		{"sequence", "hosts:\n  - a\n  - b\n", "sequences are not supported"},
+		{"flow", "tls: {min_version: 1.3}\n", "flow collections"},
+		{"tabs", "tls:\n\tmin_version: 1.3\n", "tabs"},
+		{"duplicate", "timeout_seconds: 1\ntimeout_seconds: 2\n", "duplicate key"},
+		{"indentation", "timeout_seconds: 1\n  extra: 2\n", "unexpected indentation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := yamlToJSON([]byte(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+}